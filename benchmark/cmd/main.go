@@ -9,6 +9,8 @@ import (
 	"github.com/base/base-bench/benchmark/config"
 	"github.com/base/base-bench/benchmark/flags"
 	"github.com/base/base-bench/runner"
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/compare"
 	"github.com/base/base-bench/runner/importer"
 	"github.com/urfave/cli/v2"
 
@@ -46,6 +48,29 @@ func main() {
 			Description: "Import benchmark runs from local metadata.json or remote URL into existing output metadata.json. Use --src-tag and --dest-tag to apply tags to runs, or use interactive mode.",
 			ArgsUsage:   "[metadata-file-or-url]",
 		},
+		{
+			Name:        "compare",
+			Flags:       cliapp.ProtectFlags(flags.CompareFlags),
+			Action:      CompareMain(Version),
+			Usage:       "compare two benchmark runs",
+			Description: "Compare a baseline and a candidate benchmark run. Each argument is a metadata.json file or an output directory, optionally followed by #<BenchmarkRun ID> (defaults to the latest BenchmarkRun in it), or a BenchmarkRun ID within --output-dir. Runs are matched by test config and per-block metrics are compared with a Mann-Whitney U test.",
+			ArgsUsage:   "<baseline> <candidate>",
+		},
+		{
+			Name:        "replay",
+			Flags:       cliapp.ProtectFlags(flags.ReplayFlags),
+			Action:      ReplayMain(Version),
+			Usage:       "replay recorded payloads into a client",
+			Description: "Benchmark a validator client against the payloads recorded by a previous run (payloads.jsonl in its output directory), so that clients and client versions can be compared on exactly the same blocks. The run is added to metadata.json in --output-dir.",
+			ArgsUsage:   "<payloads-file>",
+		},
+		{
+			Name:        "validate",
+			Action:      ValidateMain,
+			Usage:       "validate benchmark config files",
+			Description: "Validate benchmark config files without running them. Rejects unknown keys, unknown variable types and references to undefined payloads, and resolves every benchmark matrix.",
+			ArgsUsage:   "<config-file>...",
+		},
 	}
 	app.Flags = flags.Flags
 	app.Version = opservice.FormatVersion(Version, GitCommit, GitDate, "")
@@ -74,6 +99,23 @@ func Main(version string) cli.ActionFunc {
 	}
 }
 
+func ReplayMain(version string) cli.ActionFunc {
+	return func(cliCtx *cli.Context) error {
+		cfg := config.NewReplayCmdConfig(cliCtx)
+		if err := cfg.Check(); err != nil {
+			return fmt.Errorf("invalid CLI flags: %w", err)
+		}
+
+		l := oplog.NewLogger(oplog.AppOut(cliCtx), cfg.LogConfig())
+		oplog.SetGlobalLogHandler(l.Handler())
+		opservice.ValidateEnvVars(flags.EnvVarPrefix, flags.Flags, l)
+
+		s := runner.NewReplayService(version, cfg, l)
+
+		return s.Replay(cliCtx.Context, cfg.RecordingPath(), cfg.NodeType())
+	}
+}
+
 func ImportMain(version string) cli.ActionFunc {
 	return func(cliCtx *cli.Context) error {
 		cfg := config.NewImportCmdConfig(cliCtx)
@@ -102,6 +144,7 @@ func ImportMain(version string) cli.ActionFunc {
 		prelimSummary := &importer.ImportSummary{
 			ImportedRunsCount: len(srcMetadata.Runs),
 			ExistingRunsCount: len(destMetadata.Runs),
+			Clients:           importer.SummarizeClients(srcMetadata.Runs),
 		}
 
 		// Check for conflicts
@@ -162,6 +205,9 @@ func ImportMain(version string) cli.ActionFunc {
 		fmt.Printf("✅ Import completed successfully!\n")
 		fmt.Printf("   • Imported: %d runs\n", result.ImportedRuns)
 		fmt.Printf("   • Total runs: %d\n", result.TotalRuns)
+		for _, c := range prelimSummary.Clients {
+			fmt.Printf("   • Client: %s\n", c)
+		}
 
 		// Show if we downloaded files from URL
 		if strings.HasPrefix(cfg.SourceFile(), "http://") || strings.HasPrefix(cfg.SourceFile(), "https://") {
@@ -185,3 +231,63 @@ func ImportMain(version string) cli.ActionFunc {
 		return nil
 	}
 }
+
+func CompareMain(version string) cli.ActionFunc {
+	return func(cliCtx *cli.Context) error {
+		cfg := config.NewCompareCmdConfig(cliCtx)
+		if err := cfg.Check(); err != nil {
+			return fmt.Errorf("invalid CLI flags: %w", err)
+		}
+
+		// keep stdout clean for the report
+		l := oplog.NewLogger(os.Stderr, oplog.DefaultCLIConfig())
+		oplog.SetGlobalLogHandler(l.Handler())
+
+		service := compare.NewService(cfg, l)
+
+		report, err := service.Compare()
+		if err != nil {
+			return fmt.Errorf("compare failed: %w", err)
+		}
+
+		return compare.Write(os.Stdout, report, cfg.Format())
+	}
+}
+
+func ValidateMain(cliCtx *cli.Context) error {
+	if cliCtx.NArg() == 0 {
+		return fmt.Errorf("at least one config file is required")
+	}
+
+	numInvalid := 0
+	for _, configPath := range cliCtx.Args().Slice() {
+		if err := validateConfigFile(configPath); err != nil {
+			numInvalid++
+			fmt.Printf("❌ %s\n", configPath)
+			for _, line := range strings.Split(err.Error(), "\n") {
+				fmt.Printf("   • %s\n", line)
+			}
+			continue
+		}
+		fmt.Printf("✅ %s\n", configPath)
+	}
+
+	if numInvalid > 0 {
+		return fmt.Errorf("%d of %d config files are invalid", numInvalid, cliCtx.NArg())
+	}
+	return nil
+}
+
+func validateConfigFile(configPath string) error {
+	cfg, err := benchmark.ReadBenchmarkConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for i, b := range cfg.Benchmarks {
+		if _, err := benchmark.NewTestPlanFromConfig(b, configPath, cfg); err != nil {
+			return fmt.Errorf("benchmark %d: %w", i, err)
+		}
+	}
+	return nil
+}
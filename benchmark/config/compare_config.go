@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/base/base-bench/benchmark/flags"
+	"github.com/urfave/cli/v2"
+)
+
+// CompareCmdConfig holds configuration for the compare command
+type CompareCmdConfig struct {
+	baseline   string
+	candidate  string
+	outputDir  string
+	format     string
+	alpha      float64
+	ignoreTags []string
+}
+
+// NewCompareCmdConfig creates a new compare command configuration from CLI context
+func NewCompareCmdConfig(cliCtx *cli.Context) *CompareCmdConfig {
+	return &CompareCmdConfig{
+		baseline:   cliCtx.Args().Get(0),
+		candidate:  cliCtx.Args().Get(1),
+		outputDir:  cliCtx.String(flags.OutputDirFlagName),
+		format:     cliCtx.String(flags.CompareFormatFlagName),
+		alpha:      cliCtx.Float64(flags.CompareAlphaFlagName),
+		ignoreTags: cliCtx.StringSlice(flags.CompareIgnoreTagFlagName),
+	}
+}
+
+// Baseline returns the baseline metadata file or BenchmarkRun ID
+func (c *CompareCmdConfig) Baseline() string {
+	return c.baseline
+}
+
+// Candidate returns the candidate metadata file or BenchmarkRun ID
+func (c *CompareCmdConfig) Candidate() string {
+	return c.candidate
+}
+
+// OutputDir returns the output directory used to resolve BenchmarkRun IDs
+func (c *CompareCmdConfig) OutputDir() string {
+	return c.outputDir
+}
+
+// Format returns the report format
+func (c *CompareCmdConfig) Format() string {
+	return c.format
+}
+
+// Alpha returns the significance level
+func (c *CompareCmdConfig) Alpha() float64 {
+	return c.alpha
+}
+
+// IgnoreTags returns the test config keys ignored when matching runs
+func (c *CompareCmdConfig) IgnoreTags() []string {
+	return c.ignoreTags
+}
+
+// Check validates the compare configuration
+func (c *CompareCmdConfig) Check() error {
+	if c.baseline == "" || c.candidate == "" {
+		return fmt.Errorf("baseline and candidate are required")
+	}
+	switch c.format {
+	case "text", "json", "markdown":
+	default:
+		return fmt.Errorf("unsupported format %q", c.format)
+	}
+	if c.alpha <= 0 || c.alpha >= 1 {
+		return fmt.Errorf("alpha must be between 0 and 1")
+	}
+	return nil
+}
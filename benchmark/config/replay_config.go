@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/base/base-bench/benchmark/flags"
+	runnerconfig "github.com/base/base-bench/runner/config"
+	"github.com/urfave/cli/v2"
+)
+
+// ReplayCmdConfig holds configuration for the replay command
+type ReplayCmdConfig struct {
+	runnerconfig.Config
+	recordingPath string
+	nodeType      string
+}
+
+// NewReplayCmdConfig creates a new replay command configuration from CLI context
+func NewReplayCmdConfig(cliCtx *cli.Context) *ReplayCmdConfig {
+	return &ReplayCmdConfig{
+		Config:        runnerconfig.NewReplayConfig(cliCtx),
+		recordingPath: cliCtx.Args().First(),
+		nodeType:      cliCtx.String(flags.ReplayNodeTypeFlagName),
+	}
+}
+
+// RecordingPath returns the path of the payload recording to replay
+func (c *ReplayCmdConfig) RecordingPath() string {
+	return c.recordingPath
+}
+
+// NodeType returns the node type to replay the payloads into, or an empty
+// string to use the recorded validator node type
+func (c *ReplayCmdConfig) NodeType() string {
+	return c.nodeType
+}
+
+// Check validates the replay configuration
+func (c *ReplayCmdConfig) Check() error {
+	if c.recordingPath == "" {
+		return errors.New("payload recording is required")
+	}
+	return c.Config.Check()
+}
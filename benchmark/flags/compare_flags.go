@@ -0,0 +1,44 @@
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	CompareFormatFlagName    = "format"
+	CompareAlphaFlagName     = "alpha"
+	CompareIgnoreTagFlagName = "ignore-tag"
+)
+
+var (
+	CompareOutputDirFlag = &cli.StringFlag{
+		Name:    OutputDirFlagName,
+		Usage:   "Output directory containing metadata.json (required when comparing BenchmarkRun IDs)",
+		EnvVars: prefixEnvVars("OUTPUT_DIR"),
+	}
+
+	CompareFormatFlag = &cli.StringFlag{
+		Name:  CompareFormatFlagName,
+		Usage: "Output format (text, json or markdown)",
+		Value: "text",
+	}
+
+	CompareAlphaFlag = &cli.Float64Flag{
+		Name:  CompareAlphaFlagName,
+		Usage: "Significance level for the Mann-Whitney U test",
+		Value: 0.05,
+	}
+
+	CompareIgnoreTagFlag = &cli.StringSliceFlag{
+		Name:  CompareIgnoreTagFlagName,
+		Usage: "Test config key to ignore when matching runs (can be repeated)",
+	}
+)
+
+// CompareFlags contains the list of flags for the compare command
+var CompareFlags = []cli.Flag{
+	CompareOutputDirFlag,
+	CompareFormatFlag,
+	CompareAlphaFlag,
+	CompareIgnoreTagFlag,
+}
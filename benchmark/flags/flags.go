@@ -1,11 +1,14 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/base/base-bench/runner/flags"
 	"github.com/urfave/cli/v2"
 
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 )
 
 const EnvVarPrefix = "BASE_BENCH"
@@ -15,11 +18,21 @@ func prefixEnvVars(name string) []string {
 }
 
 const (
-	ConfigFlagName    = "config"
-	RootDirFlagName   = "root-dir"
-	OutputDirFlagName = "output-dir"
-	TxFuzzBinFlagName = "tx-fuzz-bin"
-	ProxyPortFlagName = "proxy-port"
+	ConfigFlagName      = "config"
+	RootDirFlagName     = "root-dir"
+	OutputDirFlagName   = "output-dir"
+	TxFuzzBinFlagName   = "tx-fuzz-bin"
+	ProxyPortFlagName   = "proxy-port"
+	ResumeFlagName      = "resume"
+	ParallelismFlagName = "parallelism"
+
+	ResourceSampleIntervalFlagName = "resource-sample-interval"
+	MeasureDataDirFlagName         = "measure-datadir"
+	MetricsScrapeIntervalFlagName  = "metrics-scrape-interval"
+	MetricsOutputFlagName          = "metrics-output"
+	MetricsRemoteWriteURLFlagName  = "metrics-remote-write-url"
+	TraceEndpointFlagName          = "trace-endpoint"
+	TraceFileFlagName              = "trace-file"
 )
 
 // TxFuzz defaults
@@ -62,6 +75,64 @@ var (
 		Value:   8546,
 		EnvVars: prefixEnvVars("PROXY_PORT"),
 	}
+
+	ResumeFlag = &cli.StringFlag{
+		Name:    ResumeFlagName,
+		Usage:   "BenchmarkRun ID to resume from the existing metadata.json in the output directory",
+		EnvVars: prefixEnvVars("RESUME"),
+	}
+
+	ParallelismFlag = &cli.IntFlag{
+		Name:    ParallelismFlagName,
+		Usage:   "Number of test runs to execute concurrently, each pinned to its own set of CPU cores",
+		Value:   1,
+		EnvVars: prefixEnvVars("PARALLELISM"),
+	}
+
+	ResourceSampleIntervalFlag = &cli.DurationFlag{
+		Name:    ResourceSampleIntervalFlagName,
+		Usage:   "Interval at which CPU, memory and disk IO of client processes are sampled from /proc (Linux only), 0 to disable",
+		Value:   0,
+		EnvVars: prefixEnvVars("RESOURCE_SAMPLE_INTERVAL"),
+	}
+
+	MeasureDataDirFlag = &cli.BoolFlag{
+		Name:    MeasureDataDirFlagName,
+		Usage:   "Measure the size of each client's datadir after every block",
+		Value:   false,
+		EnvVars: prefixEnvVars("MEASURE_DATADIR"),
+	}
+
+	MetricsScrapeIntervalFlag = &cli.DurationFlag{
+		Name:    MetricsScrapeIntervalFlagName,
+		Usage:   "Interval at which client metrics are scraped in the background, 0 to scrape once at the end of every block",
+		Value:   200 * time.Millisecond,
+		EnvVars: prefixEnvVars("METRICS_SCRAPE_INTERVAL"),
+	}
+
+	MetricsOutputFlag = &cli.StringSliceFlag{
+		Name:    MetricsOutputFlagName,
+		Usage:   "Additional formats to write per-block metrics in: csv, parquet or remote-write. metrics.json is always written",
+		EnvVars: prefixEnvVars("METRICS_OUTPUT"),
+	}
+
+	MetricsRemoteWriteURLFlag = &cli.StringFlag{
+		Name:    MetricsRemoteWriteURLFlagName,
+		Usage:   "Prometheus remote-write endpoint to push per-block metrics to, required by --metrics-output=remote-write",
+		EnvVars: prefixEnvVars("METRICS_REMOTE_WRITE_URL"),
+	}
+
+	TraceEndpointFlag = &cli.StringFlag{
+		Name:    TraceEndpointFlagName,
+		Usage:   "OTLP/HTTP collector to export Engine API traces to, e.g. http://localhost:4318",
+		EnvVars: prefixEnvVars("TRACE_ENDPOINT"),
+	}
+
+	TraceFileFlag = &cli.StringFlag{
+		Name:    TraceFileFlagName,
+		Usage:   "Path of a file to write Engine API traces to as JSON",
+		EnvVars: prefixEnvVars("TRACE_FILE"),
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
@@ -73,9 +144,19 @@ var RunFlags = []cli.Flag{
 	OutputDirFlag,
 	TxFuzzBinFlag,
 	ProxyPortFlag,
+	ResumeFlag,
+	ParallelismFlag,
+	ResourceSampleIntervalFlag,
+	MeasureDataDirFlag,
+	MetricsScrapeIntervalFlag,
+	MetricsOutputFlag,
+	MetricsRemoteWriteURLFlag,
+	TraceEndpointFlag,
+	TraceFileFlag,
 }
 
 func init() {
 	Flags = append(Flags, oplog.CLIFlags(EnvVarPrefix)...)
 	RunFlags = append(RunFlags, flags.CLIFlags(EnvVarPrefix)...)
+	RunFlags = append(RunFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
 }
@@ -0,0 +1,39 @@
+package flags
+
+import (
+	"github.com/base/base-bench/runner/flags"
+	"github.com/urfave/cli/v2"
+
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+)
+
+const (
+	ReplayNodeTypeFlagName = "node-type"
+)
+
+var (
+	ReplayNodeTypeFlag = &cli.StringFlag{
+		Name:    ReplayNodeTypeFlagName,
+		Usage:   "Node type of the client to replay the payloads into, defaults to the validator node type of the recorded run",
+		EnvVars: prefixEnvVars("REPLAY_NODE_TYPE"),
+	}
+)
+
+// ReplayFlags contains the list of flags for the replay command
+var ReplayFlags = []cli.Flag{
+	RootDirFlag,
+	OutputDirFlag,
+	ReplayNodeTypeFlag,
+	ResourceSampleIntervalFlag,
+	MeasureDataDirFlag,
+	MetricsScrapeIntervalFlag,
+	MetricsOutputFlag,
+	MetricsRemoteWriteURLFlag,
+	TraceEndpointFlag,
+	TraceFileFlag,
+}
+
+func init() {
+	ReplayFlags = append(ReplayFlags, flags.CLIFlags(EnvVarPrefix)...)
+	ReplayFlags = append(ReplayFlags, opmetrics.CLIFlags(EnvVarPrefix)...)
+}
@@ -210,4 +210,47 @@ export const CHART_CONFIG: Record<string, ChartConfig> = {
     description: "Shows the 90th percentile latency for account loads",
     unit: "s",
   },
+  "process/cpu_usage": {
+    type: "line",
+    title: "Client CPU Usage",
+    description:
+      "Shows the CPU cores used by the client process since the previous block",
+    unit: "count",
+  },
+  "process/rss_bytes": {
+    type: "line",
+    title: "Client Memory (RSS)",
+    description: "Shows the peak resident memory of the client process",
+    unit: "bytes",
+  },
+  "process/read_bytes": {
+    type: "line",
+    title: "Client Disk Reads",
+    description: "Shows the bytes read from storage by the client per block",
+    unit: "bytes",
+  },
+  "process/write_bytes": {
+    type: "line",
+    title: "Client Disk Writes",
+    description: "Shows the bytes written to storage by the client per block",
+    unit: "bytes",
+  },
+  "process/open_fds": {
+    type: "line",
+    title: "Client Open File Descriptors",
+    description: "Shows the peak number of open file descriptors",
+    unit: "count",
+  },
+  "process/threads": {
+    type: "line",
+    title: "Client Threads",
+    description: "Shows the peak number of client threads",
+    unit: "count",
+  },
+  "datadir/size": {
+    type: "line",
+    title: "Datadir Size",
+    description: "Shows the total size of the client's datadir after each block",
+    unit: "bytes",
+  },
 };
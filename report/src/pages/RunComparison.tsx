@@ -2,7 +2,7 @@ import { useMemo, useState } from "react";
 import ChartSelector, { SelectedData } from "../components/ChartSelector";
 import ChartGrid from "../components/ChartGrid";
 import { useTestMetadata, useMultipleDataSeries } from "../utils/useDataSeries";
-import { DataSeries } from "../types";
+import { DataSeries, hostFingerprint } from "../types";
 import { useParams } from "react-router-dom";
 import Navbar from "../components/Navbar";
 
@@ -41,6 +41,20 @@ function RunComparison() {
     };
   }, [allBenchmarkRuns, benchmarkRunId]);
 
+  // warn when the selected runs were executed on different hosts
+  const hasMixedHosts = useMemo(() => {
+    const hosts = new Set<string>();
+    for (const { outputDir } of selection) {
+      const host = benchmarkRuns.runs.find(
+        (run) => run.outputDir === outputDir,
+      )?.host;
+      if (host) {
+        hosts.add(hostFingerprint(host));
+      }
+    }
+    return hosts.size > 1;
+  }, [benchmarkRuns, selection]);
+
   const dataQueryKey = useMemo(() => {
     return selection.map(
       (query) => [query.outputDir, query.role] as [string, string],
@@ -77,6 +91,12 @@ function RunComparison() {
             onChangeDataQuery={setSelection}
             benchmarkRuns={benchmarkRuns}
           />
+          {hasMixedHosts && (
+            <div className="my-4 rounded-md bg-amber-50 px-4 py-2 text-sm text-amber-700 ring-1 ring-amber-600/20">
+              The selected runs were executed on different hosts, so their
+              results may not be comparable.
+            </div>
+          )}
           {isLoading ? "Loading..." : <ChartGrid data={data ?? []} />}
         </div>
       </div>
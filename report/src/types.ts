@@ -52,6 +52,49 @@ export interface ChartConfig {
     | "blocks"; // Add 'gas/s', ensure 's' is present
 }
 
+export interface MetricDistribution {
+  count: number;
+  mean: number;
+  stddev: number;
+  min: number;
+  max: number;
+  p50: number;
+  p90: number;
+  p99: number;
+}
+
+export interface HostInfo {
+  cpuModel?: string;
+  cpuCores: number;
+  memoryBytes?: number;
+  os: string;
+  kernel?: string;
+  filesystem?: string;
+  goVersion: string;
+  version: string;
+}
+
+// hostFingerprint identifies the hardware and OS of a host, matching
+// HostInfo.Diff in the runner.
+export const hostFingerprint = (host: HostInfo): string =>
+  [
+    host.cpuModel,
+    host.cpuCores,
+    host.memoryBytes,
+    host.os,
+    host.kernel,
+    host.filesystem,
+  ].join("|");
+
+export interface DataDirGrowth {
+  startBytes: number;
+  endBytes: number;
+  blocks: number;
+  gas: number;
+  bytesPerBlock: number;
+  bytesPerGas: number;
+}
+
 export interface BenchmarkRun {
   id: string;
   sourceFile: string;
@@ -64,6 +107,18 @@ export interface BenchmarkRun {
     warning?: Record<string, number>;
     error?: Record<string, number>;
   };
+  group?: string;
+  repetition?: number;
+  host?: HostInfo;
+  aggregate?: {
+    numRuns: number;
+    numSuccessful: number;
+    confidence: number;
+    metrics: Record<
+      string,
+      { mean: number; stddev: number; ciLower: number; ciUpper: number }
+    >;
+  };
   result: {
     success: boolean;
     complete?: boolean;
@@ -72,11 +127,14 @@ export interface BenchmarkRun {
       forkChoiceUpdated: number;
       getPayload: number;
       sendTxs?: number;
+      distributions?: Record<string, MetricDistribution>;
     };
     validatorMetrics?: {
       gasPerSecond: number;
       newPayload: number;
+      distributions?: Record<string, MetricDistribution>;
     };
+    dataDir?: Record<string, DataDirGrowth>;
   } | null;
 }
 
@@ -20,6 +20,13 @@ export const useTestMetadata = () => {
     const jsonData = await response.json();
 
     const runs = jsonData as BenchmarkRuns;
+
+    // runs that use the same node type for both roles only record NodeType,
+    // fill in the per-role node types so runs can be grouped by either role
+    for (const run of runs.runs) {
+      run.testConfig.SequencerNodeType ??= run.testConfig.NodeType;
+      run.testConfig.ValidatorNodeType ??= run.testConfig.NodeType;
+    }
     return runs;
   }, []);
 
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package runner
+
+import "runtime"
+
+// allowedCPUs returns the IDs of the CPUs the runner may run on. Without
+// affinity masks, every CPU is allowed.
+func allowedCPUs() ([]int, error) {
+	cpus := make([]int, runtime.NumCPU())
+	for i := range cpus {
+		cpus[i] = i
+	}
+	return cpus, nil
+}
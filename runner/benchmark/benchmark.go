@@ -21,6 +21,11 @@ type TestRun struct {
 	Name        string
 	Description string
 	OutputDir   string
+
+	// Group identifies the matrix cell when the cell is repeated multiple
+	// times. It is empty for cells that only run once.
+	Group      string
+	Repetition int
 }
 
 const (
@@ -34,6 +39,20 @@ var DefaultParams = &types.RunParams{
 	BlockTime: 1 * time.Second,
 }
 
+// paramTypes is the set of variable types understood by NewParamsFromValues.
+var paramTypes = map[string]bool{
+	"payload":             true,
+	"node_type":           true,
+	"sequencer_node_type": true,
+	"validator_node_type": true,
+	"gas_limit":           true,
+	"env":                 true,
+	"num_blocks":          true,
+	"block_time":          true,
+	"client_args":         true,
+	"client_flags":        true,
+}
+
 // NewParamsFromValues constructs a new benchmark params given a config and a set of transaction payloads to run.
 func NewParamsFromValues(assignments map[string]interface{}) (*types.RunParams, error) {
 	params := *DefaultParams
@@ -54,6 +73,18 @@ func NewParamsFromValues(assignments map[string]interface{}) (*types.RunParams,
 			} else {
 				return nil, fmt.Errorf("invalid node type %s", v)
 			}
+		case "sequencer_node_type":
+			if vStr, ok := v.(string); ok {
+				params.SequencerNodeType = vStr
+			} else {
+				return nil, fmt.Errorf("invalid sequencer node type %s", v)
+			}
+		case "validator_node_type":
+			if vStr, ok := v.(string); ok {
+				params.ValidatorNodeType = vStr
+			} else {
+				return nil, fmt.Errorf("invalid validator node type %s", v)
+			}
 		case "gas_limit":
 			if vInt, ok := v.(int); ok {
 				params.GasLimit = uint64(vInt)
@@ -62,14 +93,30 @@ func NewParamsFromValues(assignments map[string]interface{}) (*types.RunParams,
 			}
 		case "env":
 			if vStr, ok := v.(string); ok {
-				entries := strings.Split(vStr, ";")
-				params.Env = make(map[string]string)
-				for _, entry := range entries {
-					kv := strings.Split(entry, "=")
-					if len(kv) != 2 {
-						return nil, fmt.Errorf("invalid env entry %s", entry)
+				env, err := parseEnv(vStr)
+				if err != nil {
+					return nil, err
+				}
+				params.Env = env
+			} else if selectors, ok := v.(map[string]interface{}); ok {
+				params.ClientEnv = make(map[string]map[string]string, len(selectors))
+				for selector, value := range selectors {
+					if err := checkClientSelector(selector); err != nil {
+						return nil, err
+					}
+					valueStr, ok := value.(string)
+					if !ok {
+						return nil, fmt.Errorf("invalid env %v", value)
+					}
+					env, err := parseEnv(valueStr)
+					if err != nil {
+						return nil, err
+					}
+					if selector == "" {
+						params.Env = env
+					} else {
+						params.ClientEnv[selector] = env
 					}
-					params.Env[kv[0]] = kv[1]
 				}
 			} else {
 				return nil, fmt.Errorf("invalid env %s", v)
@@ -80,12 +127,134 @@ func NewParamsFromValues(assignments map[string]interface{}) (*types.RunParams,
 			} else {
 				return nil, fmt.Errorf("invalid num blocks %s", v)
 			}
+		case "client_args", "client_flags":
+			clientArgs, err := parseClientArgs(v)
+			if err != nil {
+				return nil, err
+			}
+			if params.ClientArgs == nil {
+				params.ClientArgs = make(map[string][]string)
+			}
+			for selector, args := range clientArgs {
+				params.ClientArgs[selector] = append(params.ClientArgs[selector], args...)
+			}
+		case "block_time":
+			blockTime, err := parseBlockTime(v)
+			if err != nil {
+				return nil, err
+			}
+			params.BlockTime = blockTime
+		default:
+			return nil, fmt.Errorf("unknown param type %s", k)
 		}
 	}
 
 	return &params, nil
 }
 
+// parseBlockTime parses a block time given either as a duration string such as
+// "200ms" or "2s", or as an integer number of milliseconds. Block times above
+// one second must be whole seconds.
+func parseBlockTime(v interface{}) (time.Duration, error) {
+	var blockTime time.Duration
+	switch value := v.(type) {
+	case int:
+		blockTime = time.Duration(value) * time.Millisecond
+	case string:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid block time %s: %w", value, err)
+		}
+		blockTime = d
+	default:
+		return 0, fmt.Errorf("invalid block time %v", v)
+	}
+
+	if blockTime <= 0 {
+		return 0, fmt.Errorf("block time must be positive, got %s", blockTime)
+	}
+
+	// L2 timestamps have second granularity, so block times above one
+	// second must be whole seconds to keep timestamps in step with blocks
+	if blockTime > time.Second && blockTime%time.Second != 0 {
+		return 0, fmt.Errorf("block time %s above 1s must be a whole number of seconds", blockTime)
+	}
+	return blockTime, nil
+}
+
+// checkClientSelector checks that a node type, role or "<node_type>/<role>"
+// selector names a valid role.
+func checkClientSelector(selector string) error {
+	if _, role, ok := strings.Cut(selector, "/"); ok && role != types.SequencerRole && role != types.ValidatorRole {
+		return fmt.Errorf("invalid client selector %s: role must be %s or %s", selector, types.SequencerRole, types.ValidatorRole)
+	}
+	return nil
+}
+
+// parseEnv parses env vars given as "KEY=value" pairs separated by ";".
+func parseEnv(value string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid env entry %s", entry)
+		}
+		env[key] = val
+	}
+	return env, nil
+}
+
+// parseArgs splits a string of args on whitespace, or takes a list of args
+// as is.
+func parseArgs(v interface{}) ([]string, error) {
+	switch value := v.(type) {
+	case string:
+		return strings.Fields(value), nil
+	case []interface{}:
+		args := make([]string, 0, len(value))
+		for _, arg := range value {
+			argStr, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid client arg %v", arg)
+			}
+			args = append(args, argStr)
+		}
+		return args, nil
+	}
+	return nil, fmt.Errorf("invalid client args %v", v)
+}
+
+// parseClientArgs parses extra client args given either as args for all
+// clients, or as a map from a node type, role or "<node_type>/<role>" selector
+// to args.
+func parseClientArgs(v interface{}) (map[string][]string, error) {
+	selectors, ok := v.(map[string]interface{})
+	if !ok {
+		args, err := parseArgs(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]string{"": args}, nil
+	}
+
+	clientArgs := make(map[string][]string, len(selectors))
+	for selector, value := range selectors {
+		if err := checkClientSelector(selector); err != nil {
+			return nil, err
+		}
+		args, err := parseArgs(value)
+		if err != nil {
+			return nil, err
+		}
+		clientArgs[selector] = args
+	}
+	return clientArgs, nil
+}
+
 const MAX_GAS_LIMIT = math.MaxUint64
 
 var cachedGenesis atomic.Pointer[core.Genesis]
@@ -0,0 +1,33 @@
+package benchmark
+
+// ConsistencyResult is the outcome of checking the blocks imported by the
+// validator against the payloads built by the sequencer.
+type ConsistencyResult struct {
+	// BlocksChecked is the number of test blocks compared.
+	BlocksChecked int `json:"blocksChecked"`
+	// Divergence describes the first block where the validator disagrees
+	// with the sequencer, if any.
+	Divergence *BlockDivergence `json:"divergence,omitempty"`
+}
+
+// BlockDivergence describes a block for which the validator computed a
+// different result than the sequencer.
+type BlockDivergence struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	// Fields lists the header fields that differ, e.g. "stateRoot". It is
+	// empty if the validator rejected the block.
+	Fields    []string          `json:"fields,omitempty"`
+	Sequencer map[string]string `json:"sequencer"`
+	Validator map[string]string `json:"validator,omitempty"`
+	// ValidationError is the error returned by the validator's
+	// engine_newPayload call, if it rejected the block.
+	ValidationError string `json:"validationError,omitempty"`
+	// TraceFiles are the debug_traceBlock dumps written to the output
+	// directory, keyed by role.
+	TraceFiles map[string]string `json:"traceFiles,omitempty"`
+}
+
+// HasDivergence returns true if the validator diverged from the sequencer.
+func (r *RunResult) HasDivergence() bool {
+	return r.Consistency != nil && r.Consistency.Divergence != nil
+}
@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+)
+
+// DataDirGrowth summarizes how much a client's datadir grew over the
+// measured blocks of a run.
+type DataDirGrowth struct {
+	StartBytes float64 `json:"startBytes"`
+	EndBytes   float64 `json:"endBytes"`
+	// Blocks and Gas are the number of blocks and total gas processed between
+	// the first and last measurement.
+	Blocks        int     `json:"blocks"`
+	Gas           float64 `json:"gas"`
+	BytesPerBlock float64 `json:"bytesPerBlock"`
+	BytesPerGas   float64 `json:"bytesPerGas"`
+}
+
+// NewDataDirGrowth computes the datadir growth from per-block metrics. It
+// returns nil if the datadir was measured on fewer than two blocks.
+func NewDataDirGrowth(blockMetrics []metrics.BlockMetrics) *DataDirGrowth {
+	first, last := -1, -1
+	for i, m := range blockMetrics {
+		if _, ok := m.GetMetricFloat(metrics.DataDirSizeMetric); ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == last {
+		return nil
+	}
+
+	growth := &DataDirGrowth{
+		Blocks: last - first,
+	}
+	growth.StartBytes, _ = blockMetrics[first].GetMetricFloat(metrics.DataDirSizeMetric)
+	growth.EndBytes, _ = blockMetrics[last].GetMetricFloat(metrics.DataDirSizeMetric)
+
+	// the first measurement already includes the first block
+	for _, m := range blockMetrics[first+1 : last+1] {
+		if gas, ok := m.GetMetricFloat(types.GasPerBlockMetric); ok {
+			growth.Gas += gas
+		}
+	}
+
+	bytes := growth.EndBytes - growth.StartBytes
+	growth.BytesPerBlock = bytes / float64(growth.Blocks)
+	if growth.Gas > 0 {
+		growth.BytesPerGas = bytes / growth.Gas
+	}
+	return growth
+}
@@ -0,0 +1,43 @@
+package benchmark_test
+
+import (
+	"testing"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/stretchr/testify/require"
+)
+
+func blockWithDataDir(size float64, gas float64) metrics.BlockMetrics {
+	m := metrics.NewBlockMetrics()
+	m.AddExecutionMetric(metrics.DataDirSizeMetric, size)
+	m.AddExecutionMetric(types.GasPerBlockMetric, gas)
+	return *m
+}
+
+func TestNewDataDirGrowth(t *testing.T) {
+	growth := benchmark.NewDataDirGrowth([]metrics.BlockMetrics{
+		blockWithDataDir(1000, 50),
+		blockWithDataDir(1500, 100),
+		blockWithDataDir(3000, 150),
+	})
+	require.Equal(t, &benchmark.DataDirGrowth{
+		StartBytes:    1000,
+		EndBytes:      3000,
+		Blocks:        2,
+		Gas:           250,
+		BytesPerBlock: 1000,
+		BytesPerGas:   8,
+	}, growth)
+}
+
+func TestNewDataDirGrowthNotMeasured(t *testing.T) {
+	require.Nil(t, benchmark.NewDataDirGrowth(nil))
+
+	m := metrics.NewBlockMetrics()
+	m.AddExecutionMetric(types.GasPerBlockMetric, 100.0)
+	require.Nil(t, benchmark.NewDataDirGrowth([]metrics.BlockMetrics{*m, *m}))
+
+	require.Nil(t, benchmark.NewDataDirGrowth([]metrics.BlockMetrics{blockWithDataDir(1000, 100)}))
+}
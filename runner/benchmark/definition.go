@@ -8,6 +8,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/base/base-bench/runner/metrics"
 	"github.com/base/base-bench/runner/payload"
 )
 
@@ -99,14 +100,54 @@ type TestDefinition struct {
 	Tags         *map[string]string   `yaml:"tags"`
 	Variables    []Param              `yaml:"variables"`
 	ProofProgram *ProofProgramOptions `yaml:"proof_program"`
+	// Repetitions is the number of times each matrix cell is run. Defaults to 1.
+	Repetitions *int `yaml:"repetitions"`
+	// VerifyConsistency checks that the validator's state root, receipts
+	// root and logs bloom match the sequencer's for every test block.
+	VerifyConsistency *bool `yaml:"verify_consistency"`
+	// ClientMetrics selects the metrics scraped from each node type,
+	// overriding the metrics profile and the clients' built-in selection.
+	ClientMetrics metrics.MetricsProfile `yaml:"client_metrics"`
+	// Exclude removes matrix cells matching any of the rules.
+	Exclude []MatrixRule `yaml:"exclude"`
+	// Include extends matching matrix cells or adds new cells after
+	// exclusions are applied.
+	Include []MatrixRule `yaml:"include"`
 }
 
+// MatrixRule maps variable types to values. A rule matches a matrix cell if
+// every variable in the rule has the same value in the cell.
+type MatrixRule map[string]interface{}
+
 func (bc *TestDefinition) Check() error {
+	if bc.Repetitions != nil && *bc.Repetitions < 1 {
+		return errors.New("repetitions must be at least 1")
+	}
+	variables := make(map[string]bool)
 	for _, b := range bc.Variables {
 		err := b.Check()
 		if err != nil {
 			return err
 		}
+		variables[b.ParamType] = true
+	}
+	for i, rule := range bc.Exclude {
+		if len(rule) == 0 {
+			return fmt.Errorf("exclude rule %d is empty", i)
+		}
+		for k := range rule {
+			if !variables[k] {
+				return fmt.Errorf("exclude rule %d references %s, which is not a variable of the benchmark", i, k)
+			}
+		}
+	}
+	for i, rule := range bc.Include {
+		if len(rule) == 0 {
+			return fmt.Errorf("include rule %d is empty", i)
+		}
+	}
+	if err := bc.ClientMetrics.Check(); err != nil {
+		return fmt.Errorf("invalid client_metrics: %w", err)
 	}
 	return nil
 }
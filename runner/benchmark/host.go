@@ -0,0 +1,149 @@
+package benchmark
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HostInfo describes the machine and build that produced a run, so that
+// results from different hosts can be told apart.
+type HostInfo struct {
+	CPUModel    string `json:"cpuModel,omitempty"`
+	CPUCores    int    `json:"cpuCores"`
+	MemoryBytes uint64 `json:"memoryBytes,omitempty"`
+	OS          string `json:"os"`
+	Kernel      string `json:"kernel,omitempty"`
+	// Filesystem is the filesystem type of the root directory, e.g. "ext4".
+	Filesystem string `json:"filesystem,omitempty"`
+	GoVersion  string `json:"goVersion"`
+	// Version is the base-bench version string.
+	Version string `json:"version"`
+}
+
+// CollectHostInfo fingerprints the current host. Details that cannot be read,
+// e.g. on systems without /proc, are left empty.
+func CollectHostInfo(rootDir string, version string) *HostInfo {
+	host := &HostInfo{
+		CPUCores:  runtime.NumCPU(),
+		OS:        runtime.GOOS,
+		GoVersion: runtime.Version(),
+		Version:   version,
+	}
+
+	if cpuinfo, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+		host.CPUModel = parseCPUModel(string(cpuinfo))
+	}
+	if meminfo, err := os.ReadFile("/proc/meminfo"); err == nil {
+		host.MemoryBytes = parseMemTotal(string(meminfo))
+	}
+	if release, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		host.Kernel = strings.TrimSpace(string(release))
+	}
+	if mounts, err := os.ReadFile("/proc/mounts"); err == nil {
+		host.Filesystem = filesystemType(string(mounts), rootDir)
+	}
+
+	return host
+}
+
+// Diff returns the names of the hardware and OS fields that differ between
+// two hosts.
+func (h *HostInfo) Diff(other *HostInfo) []string {
+	if h == nil || other == nil {
+		return nil
+	}
+
+	var fields []string
+	if h.CPUModel != other.CPUModel {
+		fields = append(fields, "cpuModel")
+	}
+	if h.CPUCores != other.CPUCores {
+		fields = append(fields, "cpuCores")
+	}
+	if h.MemoryBytes != other.MemoryBytes {
+		fields = append(fields, "memoryBytes")
+	}
+	if h.OS != other.OS || h.Kernel != other.Kernel {
+		fields = append(fields, "kernel")
+	}
+	if h.Filesystem != other.Filesystem {
+		fields = append(fields, "filesystem")
+	}
+	return fields
+}
+
+// parseCPUModel returns the CPU model from the contents of /proc/cpuinfo.
+func parseCPUModel(cpuinfo string) string {
+	scanner := bufio.NewScanner(strings.NewReader(cpuinfo))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		// x86 reports "model name", some arm kernels only report "Model"
+		switch strings.TrimSpace(key) {
+		case "model name", "Model":
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// parseMemTotal returns the total memory in bytes from the contents of
+// /proc/meminfo.
+func parseMemTotal(meminfo string) uint64 {
+	scanner := bufio.NewScanner(strings.NewReader(meminfo))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
+
+// filesystemType returns the type of the filesystem that dir is on, using the
+// longest matching mount point in the contents of /proc/mounts.
+func filesystemType(mounts string, dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	fsType := ""
+	longest := -1
+	scanner := bufio.NewScanner(strings.NewReader(mounts))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		// spaces in mount points are escaped as \040
+		mountPoint := strings.ReplaceAll(fields[1], `\040`, " ")
+		if !isSubPath(mountPoint, dir) || len(mountPoint) <= longest {
+			continue
+		}
+		fsType = fields[2]
+		longest = len(mountPoint)
+	}
+	return fsType
+}
+
+func isSubPath(parent string, dir string) bool {
+	if parent == "/" || parent == dir {
+		return true
+	}
+	return strings.HasPrefix(dir, strings.TrimSuffix(parent, "/")+"/")
+}
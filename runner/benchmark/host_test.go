@@ -0,0 +1,31 @@
+package benchmark_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectHostInfo(t *testing.T) {
+	host := benchmark.CollectHostInfo(t.TempDir(), "v1.0.0")
+	require.Equal(t, runtime.NumCPU(), host.CPUCores)
+	require.Equal(t, runtime.GOOS, host.OS)
+	require.Equal(t, runtime.Version(), host.GoVersion)
+	require.Equal(t, "v1.0.0", host.Version)
+}
+
+func TestHostInfo_Diff(t *testing.T) {
+	a := &benchmark.HostInfo{CPUModel: "AMD EPYC 9R14", CPUCores: 96, MemoryBytes: 1 << 40, OS: "linux", Kernel: "6.8.0", Filesystem: "ext4", Version: "v1"}
+
+	b := *a
+	b.Version = "v2"
+	require.Empty(t, a.Diff(&b), "software versions do not make a different host")
+
+	b.CPUCores = 48
+	b.Filesystem = "xfs"
+	require.Equal(t, []string{"cpuCores", "filesystem"}, a.Diff(&b))
+
+	require.Empty(t, a.Diff(nil), "runs without host info cannot be compared")
+}
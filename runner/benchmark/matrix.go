@@ -3,6 +3,8 @@ package benchmark
 import (
 	"fmt"
 	"time"
+
+	"github.com/base/base-bench/runner/metrics"
 )
 
 type ThresholdConfig struct {
@@ -16,6 +18,9 @@ type TestPlan struct {
 	Snapshot     *SnapshotDefinition
 	ProofProgram *ProofProgramOptions
 	Thresholds   *ThresholdConfig
+
+	VerifyConsistency bool
+	ClientMetrics     metrics.MetricsProfile
 }
 
 func NewTestPlanFromConfig(c TestDefinition, testFileName string, config *BenchmarkConfig) (*TestPlan, error) {
@@ -40,11 +45,23 @@ func NewTestPlanFromConfig(c TestDefinition, testFileName string, config *Benchm
 		Snapshot:     c.Snapshot,
 		ProofProgram: proofProgram,
 		Thresholds:   c.Metrics,
+
+		VerifyConsistency: c.VerifyConsistency != nil && *c.VerifyConsistency,
+		ClientMetrics:     c.ClientMetrics,
 	}, nil
 }
 
 // ResolveTestRunsFromMatrix constructs a new ParamsMatrix from a config.
 func ResolveTestRunsFromMatrix(c TestDefinition, testFileName string, config *BenchmarkConfig) ([]TestRun, error) {
+	if err := c.Check(); err != nil {
+		return nil, err
+	}
+
+	repetitions := 1
+	if c.Repetitions != nil {
+		repetitions = *c.Repetitions
+	}
+
 	seenParams := make(map[string]bool)
 
 	// Multiple payloads can run in a single benchmark.
@@ -77,29 +94,48 @@ func ResolveTestRunsFromMatrix(c TestDefinition, testFileName string, config *Be
 		}
 	}
 
-	// Ensure total params is less than the max
+	// Expand the cartesian product of all variables
 	totalParams := 1
 	for _, d := range dimensions {
 		totalParams *= d
 	}
 
-	if totalParams > MaxTotalParams {
-		return nil, fmt.Errorf("total number of params %d exceeds max %d", totalParams, MaxTotalParams)
-	}
-
 	currentParams := make([]int, len(dimensions))
-
-	// Create the params matrix
-	testParams := make([]TestRun, totalParams)
-
-	id := fmt.Sprintf("test-%d", time.Now().UnixMicro())
+	cells := make([]MatrixRule, 0, totalParams)
 
 	for i := 0; i < totalParams; i++ {
-		valueSelections := make(map[string]interface{})
+		valueSelections := make(MatrixRule)
 		for j, p := range params {
 			valueSelections[p.ParamType] = valuesByParam[j][currentParams[j]]
 		}
+		cells = append(cells, valueSelections)
+
+		// Increment current params from the rightmost param
+		for incIdx := len(dimensions) - 1; incIdx >= 0; incIdx-- {
+			// find the next param that is incrementable
+			if currentParams[incIdx] < dimensions[incIdx]-1 {
+				currentParams[incIdx]++
+				break
+			} else {
+				// If this param is currently at the max, reset it to 0 and continue to the next param
+				currentParams[incIdx] = 0
+			}
+		}
+	}
 
+	cells = applyMatrixRules(cells, params, c.Exclude, c.Include)
+
+	// Ensure the total number of runs, counting repetitions, is less than the max
+	if len(cells)*repetitions > MaxTotalParams {
+		return nil, fmt.Errorf("total number of runs %d (%d params x %d repetitions) exceeds max %d", len(cells)*repetitions, len(cells), repetitions, MaxTotalParams)
+	}
+
+	// Create the params matrix
+	testParams := make([]TestRun, 0, len(cells)*repetitions)
+
+	id := fmt.Sprintf("test-%d", time.Now().UnixMicro())
+
+	for i, valueSelections := range cells {
 		params, err := NewParamsFromValues(valueSelections)
 		if err != nil {
 			return nil, err
@@ -114,34 +150,121 @@ func ResolveTestRunsFromMatrix(c TestDefinition, testFileName string, config *Be
 			params.Tags = *c.Tags
 		}
 
-		testParams[i] = TestRun{
-			ID:          id,
-			Params:      *params,
-			OutputDir:   fmt.Sprintf("%s-%d", id, i),
-			Name:        params.Name,
-			Description: params.Description,
-			TestFile:    testFileName,
+		for rep := 0; rep < repetitions; rep++ {
+			testRun := TestRun{
+				ID:          id,
+				Params:      *params,
+				OutputDir:   fmt.Sprintf("%s-%d", id, i),
+				Name:        params.Name,
+				Description: params.Description,
+				TestFile:    testFileName,
+			}
+			if repetitions > 1 {
+				testRun.Group = testRun.OutputDir
+				testRun.Repetition = rep
+				testRun.OutputDir = fmt.Sprintf("%s-%d-%d", id, i, rep)
+			}
+			testParams = append(testParams, testRun)
+		}
+	}
+
+	return testParams, nil
+}
+
+// matrixValuesEqual compares two variable values, treating string pointers as
+// their underlying string.
+func matrixValuesEqual(a interface{}, b interface{}) bool {
+	if p, ok := a.(*string); ok && p != nil {
+		a = *p
+	}
+	if p, ok := b.(*string); ok && p != nil {
+		b = *p
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// matches returns true if every variable in the rule that is present in keys
+// has the same value in the cell.
+func (r MatrixRule) matches(cell MatrixRule, keys map[string]bool) bool {
+	for k, v := range r {
+		if !keys[k] {
+			continue
+		}
+		if !matrixValuesEqual(cell[k], v) {
+			return false
 		}
+	}
+	return true
+}
 
-		done := true
+// excludes returns true if the cell has every variable in the rule with the
+// same value. Unlike include rules, exclude rules only reference matrix
+// variables, so a variable missing from the cell never matches.
+func (r MatrixRule) excludes(cell MatrixRule) bool {
+	for k, v := range r {
+		value, ok := cell[k]
+		if !ok || !matrixValuesEqual(value, v) {
+			return false
+		}
+	}
+	return true
+}
 
-		// Increment current params from the rightmost param
-		for incIdx := len(dimensions) - 1; incIdx >= 0; incIdx-- {
-			// find the next param that is incrementable
-			if currentParams[incIdx] < dimensions[incIdx]-1 {
-				currentParams[incIdx]++
-				done = false
-				break
-			} else {
-				// If this param is currently at the max, reset it to 0 and continue to the next param
-				currentParams[incIdx] = 0
+// applyMatrixRules filters and extends the cartesian product of the matrix
+// variables following the semantics of GitHub Actions matrices:
+//
+//   - a cell is removed if it matches any exclude rule.
+//   - an include rule is merged into every remaining cell whose matrix
+//     variables match the rule, adding its other variables without
+//     overwriting the original matrix values.
+//   - an include rule that matches no cell is added as a new cell, starting
+//     from the single-valued variables of the matrix.
+func applyMatrixRules(cells []MatrixRule, params []Param, exclude []MatrixRule, include []MatrixRule) []MatrixRule {
+	matrixKeys := make(map[string]bool, len(params))
+	for _, p := range params {
+		matrixKeys[p.ParamType] = true
+	}
+
+	filtered := make([]MatrixRule, 0, len(cells))
+cellLoop:
+	for _, cell := range cells {
+		for _, rule := range exclude {
+			if rule.excludes(cell) {
+				continue cellLoop
 			}
 		}
+		filtered = append(filtered, cell)
+	}
 
-		if done {
-			break
+	originalCells := len(filtered)
+	for _, rule := range include {
+		matched := false
+		for _, cell := range filtered[:originalCells] {
+			if !rule.matches(cell, matrixKeys) {
+				continue
+			}
+			matched = true
+			for k, v := range rule {
+				if !matrixKeys[k] {
+					cell[k] = v
+				}
+			}
 		}
+		if matched {
+			continue
+		}
+
+		cell := make(MatrixRule)
+		for _, p := range params {
+			if p.Values == nil {
+				cell[p.ParamType] = p.Value
+			}
+		}
+		for k, v := range rule {
+			cell[k] = v
+		}
+		filtered = append(filtered, cell)
 	}
 
-	return testParams, nil
+	return filtered
 }
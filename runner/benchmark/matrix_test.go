@@ -1,7 +1,9 @@
 package benchmark_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/base/base-bench/runner/benchmark"
 	"github.com/base/base-bench/runner/network/types"
@@ -158,6 +160,324 @@ func TestResolveTestRunsFromMatrix(t *testing.T) {
 	}
 }
 
+func TestResolveTestRunsFromMatrix_Repetitions(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+	repetitions := 3
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Repetitions: &repetitions,
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     stringPtr("simple"),
+			},
+			{
+				ParamType: "node_type",
+				Values:    []interface{}{"geth", "reth"},
+			},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 6)
+
+	outputDirs := make(map[string]bool)
+	groups := make(map[string]int)
+	for _, run := range got {
+		require.NotEmpty(t, run.Group)
+		outputDirs[run.OutputDir] = true
+		groups[run.Group]++
+	}
+	require.Len(t, outputDirs, 6)
+	require.Len(t, groups, 2)
+	for _, count := range groups {
+		require.Equal(t, 3, count)
+	}
+
+	repetitions = 0
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Repetitions: &repetitions,
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     stringPtr("simple"),
+			},
+		},
+	}, "", config)
+	require.Error(t, err)
+
+	// repetitions count towards the maximum number of runs
+	repetitions = benchmark.MaxTotalParams/2 + 1
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Repetitions: &repetitions,
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     stringPtr("simple"),
+			},
+			{
+				ParamType: "node_type",
+				Values:    []interface{}{"geth", "reth"},
+			},
+		},
+	}, "", config)
+	require.ErrorContains(t, err, "exceeds max")
+}
+
+func TestResolveTestRunsFromMatrix_IncludeExclude(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "node_type",
+				Values:    []interface{}{"geth", "reth", "rbuilder"},
+			},
+			{
+				ParamType: "gas_limit",
+				Values:    []interface{}{15000000, 30000000},
+			},
+		},
+		Exclude: []benchmark.MatrixRule{
+			{"node_type": "rbuilder", "gas_limit": 15000000},
+		},
+		Include: []benchmark.MatrixRule{
+			// extends the matching reth cells
+			{"node_type": "reth", "env": "RUST_LOG=debug"},
+			// adds a new cell
+			{"node_type": "geth", "gas_limit": 60000000, "env": "GOGC=200"},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 6)
+
+	cells := make(map[string]types.RunParams)
+	for _, run := range got {
+		cells[fmt.Sprintf("%s-%d", run.Params.NodeType, run.Params.GasLimit)] = run.Params
+	}
+	require.NotContains(t, cells, "rbuilder-15000000")
+	require.Contains(t, cells, "rbuilder-30000000")
+	require.Equal(t, map[string]string{"RUST_LOG": "debug"}, cells["reth-15000000"].Env)
+	require.Equal(t, map[string]string{"RUST_LOG": "debug"}, cells["reth-30000000"].Env)
+	require.Nil(t, cells["geth-15000000"].Env)
+
+	added := cells["geth-60000000"]
+	require.Equal(t, "simple", added.PayloadID)
+	require.Equal(t, map[string]string{"GOGC": "200"}, added.Env)
+
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+		},
+		Exclude: []benchmark.MatrixRule{
+			{"node_type": "geth"},
+		},
+	}, "", config)
+	require.ErrorContains(t, err, "not a variable of the benchmark")
+
+	// a rule with one undefined key must not widen to the remaining keys
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "node_type",
+				Values:    []interface{}{"geth", "reth"},
+			},
+		},
+		Exclude: []benchmark.MatrixRule{
+			{"node_type": "geth", "block_time": "2s"},
+		},
+	}, "", config)
+	require.ErrorContains(t, err, "block_time, which is not a variable of the benchmark")
+}
+
+func TestResolveTestRunsFromMatrix_BlockTime(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "block_time",
+				Values:    []interface{}{"200ms", 2000},
+			},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, 200*time.Millisecond, got[0].Params.BlockTime)
+	require.Equal(t, 2*time.Second, got[1].Params.BlockTime)
+
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "block_time",
+				Value:     "0s",
+			},
+		},
+	}, "", config)
+	require.Error(t, err)
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "block_time",
+				Value:     "1500ms",
+			},
+		},
+	}, "", config)
+	require.ErrorContains(t, err, "must be a whole number of seconds")
+}
+
+func TestResolveTestRunsFromMatrix_ClientArgs(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "node_type",
+				Value:     "reth",
+			},
+			{
+				ParamType: "client_args",
+				Values: []interface{}{
+					"--engine.persistence-threshold 0",
+					map[string]interface{}{
+						"reth":           "--engine.memory-block-buffer-target 0",
+						"reth/validator": []interface{}{"--engine.persistence-threshold", "0"},
+						"geth":           "--cache 4096",
+					},
+				},
+			},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	all := got[0].Params
+	require.Equal(t, []string{"--engine.persistence-threshold", "0"}, all.ClientArgsFor(benchmark.SequencerRole))
+	require.Equal(t, []string{"--engine.persistence-threshold", "0"}, all.ClientArgsFor(benchmark.ValidatorRole))
+	require.Equal(t, "--engine.persistence-threshold 0", all.ToConfig()["ClientArgs"])
+
+	perRole := got[1].Params
+	require.Equal(t, []string{"--engine.memory-block-buffer-target", "0"}, perRole.ClientArgsFor(benchmark.SequencerRole))
+	require.Equal(t, []string{"--engine.memory-block-buffer-target", "0", "--engine.persistence-threshold", "0"}, perRole.ClientArgsFor(benchmark.ValidatorRole))
+	require.Equal(t, "geth: --cache 4096; reth: --engine.memory-block-buffer-target 0; reth/validator: --engine.persistence-threshold 0", perRole.ToConfig()["ClientArgs"])
+
+	_, err = benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "client_flags",
+				Value:     map[string]interface{}{"reth/builder": "--foo"},
+			},
+		},
+	}, "", config)
+	require.Error(t, err)
+}
+
+func TestResolveTestRunsFromMatrix_Env(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "node_type",
+				Value:     "geth",
+			},
+			{
+				ParamType: "env",
+				Value: map[string]interface{}{
+					"":          "GOGC=100;GOMAXPROCS=4",
+					"validator": "GOGC=off",
+				},
+			},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	params := got[0].Params
+	require.Equal(t, map[string]string{"GOGC": "100", "GOMAXPROCS": "4"}, params.EnvFor(benchmark.SequencerRole))
+	require.Equal(t, map[string]string{"GOGC": "off", "GOMAXPROCS": "4"}, params.EnvFor(benchmark.ValidatorRole))
+	require.Equal(t, "GOGC=100;GOMAXPROCS=4; validator: GOGC=off", params.ToConfig()["Env"])
+	require.Equal(t, "GOGC=100;GOMAXPROCS=4", params.ToConfig()["SequencerEnv"])
+	require.Equal(t, "GOGC=off;GOMAXPROCS=4", params.ToConfig()["ValidatorEnv"])
+	require.True(t, params.HasEnv())
+}
+
+func TestResolveTestRunsFromMatrix_RoleNodeTypes(t *testing.T) {
+	config := &benchmark.BenchmarkConfig{Name: "test"}
+
+	got, err := benchmark.ResolveTestRunsFromMatrix(benchmark.TestDefinition{
+		Variables: []benchmark.Param{
+			{
+				ParamType: "payload",
+				Value:     "simple",
+			},
+			{
+				ParamType: "sequencer_node_type",
+				Values:    []interface{}{"rbuilder", "geth"},
+			},
+			{
+				ParamType: "validator_node_type",
+				Value:     "reth",
+			},
+			{
+				ParamType: "client_args",
+				Value:     map[string]interface{}{"reth/validator": "--engine.persistence-threshold 0"},
+			},
+		},
+	}, "", config)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	params := got[0].Params
+	require.Equal(t, "rbuilder", params.NodeTypeFor(benchmark.SequencerRole))
+	require.Equal(t, "reth", params.NodeTypeFor(benchmark.ValidatorRole))
+	require.Empty(t, params.ClientArgsFor(benchmark.SequencerRole))
+	require.Equal(t, []string{"--engine.persistence-threshold", "0"}, params.ClientArgsFor(benchmark.ValidatorRole))
+
+	testConfig := params.ToConfig()
+	// without a node_type variable NodeType keeps the default
+	require.Equal(t, "geth", testConfig["NodeType"])
+	require.Equal(t, "rbuilder", testConfig["SequencerNodeType"])
+	require.Equal(t, "reth", testConfig["ValidatorNodeType"])
+	require.Equal(t, "rbuilder/reth", params.NodeTypeLabel())
+	require.Equal(t, "geth", got[1].Params.ToConfig()["NodeType"])
+	require.Equal(t, "geth/reth", got[1].Params.NodeTypeLabel())
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
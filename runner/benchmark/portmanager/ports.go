@@ -3,6 +3,7 @@ package portmanager
 import (
 	"fmt"
 	"net"
+	"sync"
 	"time"
 )
 
@@ -23,6 +24,10 @@ type PortManager interface {
 type portManager struct {
 	// ports is a map of node type to a map of port purpose to port number.
 	ports map[uint64]struct{}
+
+	// lock guards ports so that clients of parallel test runs can acquire
+	// ports concurrently.
+	lock sync.Mutex
 }
 
 func NewPortManager() PortManager {
@@ -32,6 +37,9 @@ func NewPortManager() PortManager {
 }
 
 func (p *portManager) AcquirePort(nodeType string, purpose PortPurpose) uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	// find the next available port number
 	for port := uint64(10000); port < 65535; port++ {
 		if _, exists := p.ports[port]; !exists {
@@ -50,6 +58,9 @@ func (p *portManager) AcquirePort(nodeType string, purpose PortPurpose) uint64 {
 }
 
 func (p *portManager) ReleasePort(portNumber uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	if _, exists := p.ports[portNumber]; !exists {
 		return
 	}
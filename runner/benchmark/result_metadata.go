@@ -1,8 +1,13 @@
 package benchmark
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	clienttypes "github.com/base/base-bench/runner/clients/types"
+	"github.com/base/base-bench/runner/metrics"
 	"github.com/base/base-bench/runner/network/types"
 )
 
@@ -11,6 +16,14 @@ type RunResult struct {
 	Complete         bool                      `json:"complete"`
 	SequencerMetrics types.SequencerKeyMetrics `json:"sequencerMetrics"`
 	ValidatorMetrics types.ValidatorKeyMetrics `json:"validatorMetrics"`
+	// ThresholdBreaches lists the warning and error thresholds exceeded by this run.
+	ThresholdBreaches []ThresholdBreach `json:"thresholdBreaches,omitempty"`
+	// Consistency is set if the validator's blocks were checked against the
+	// sequencer's payloads.
+	Consistency *ConsistencyResult `json:"consistency,omitempty"`
+	// DataDir is the datadir growth of each client over the run, keyed by
+	// role.
+	DataDir map[string]*DataDirGrowth `json:"dataDir,omitempty"`
 }
 
 // Run is the output JSON metadata for a benchmark run.
@@ -24,6 +37,101 @@ type Run struct {
 	Result          *RunResult             `json:"result"`
 	Thresholds      *ThresholdConfig       `json:"thresholds"`
 	CreatedAt       *time.Time             `json:"createdAt"`
+
+	// Group and Repetition identify repeated runs of the same matrix cell.
+	Group      string `json:"group,omitempty"`
+	Repetition int    `json:"repetition,omitempty"`
+	// Aggregate summarizes all completed runs in the group. It is shared by
+	// every run in the group.
+	Aggregate *AggregateResult `json:"aggregate,omitempty"`
+	// Resources records the execution slot and CPU cores the run was pinned
+	// to when running with --parallelism greater than 1.
+	Resources *ResourceAssignment `json:"resources,omitempty"`
+	// Clients identifies the client binaries that produced the results,
+	// keyed by role.
+	Clients map[string]*clienttypes.ClientVersion `json:"clients,omitempty"`
+	// Host describes the machine the run was executed on.
+	Host *HostInfo `json:"host,omitempty"`
+}
+
+// ResourceAssignment describes the host resources reserved for a run.
+type ResourceAssignment struct {
+	Slot   int    `json:"slot"`
+	CPUSet string `json:"cpuSet,omitempty"`
+}
+
+// AggregateMetric is the mean of a key metric across repeated runs along with
+// its 95% confidence interval.
+type AggregateMetric struct {
+	Mean    float64 `json:"mean"`
+	StdDev  float64 `json:"stddev"`
+	CILower float64 `json:"ciLower"`
+	CIUpper float64 `json:"ciUpper"`
+}
+
+// AggregateResult summarizes the key metrics of repeated runs of a matrix cell.
+type AggregateResult struct {
+	NumRuns       int                        `json:"numRuns"`
+	NumSuccessful int                        `json:"numSuccessful"`
+	Confidence    float64                    `json:"confidence"`
+	Metrics       map[string]AggregateMetric `json:"metrics"`
+}
+
+// keyMetricsByName returns the key metric values of a run result keyed by
+// "<role>/<metric>". Latencies are in seconds.
+func keyMetricsByName(result *RunResult) map[string]float64 {
+	return map[string]float64{
+		SequencerRole + "/" + types.UpdateForkChoiceLatencyMetric: result.SequencerMetrics.AverageFCULatency,
+		SequencerRole + "/" + types.GetPayloadLatencyMetric:       result.SequencerMetrics.AverageGetPayloadLatency,
+		SequencerRole + "/" + types.SendTxsLatencyMetric:          result.SequencerMetrics.AverageSendTxsLatency,
+		SequencerRole + "/" + types.GasPerSecondMetric:            result.SequencerMetrics.AverageGasPerSecond,
+		ValidatorRole + "/" + types.NewPayloadLatencyMetric:       result.ValidatorMetrics.AverageNewPayloadLatency,
+		ValidatorRole + "/" + types.GasPerSecondMetric:            result.ValidatorMetrics.AverageGasPerSecond,
+	}
+}
+
+// updateAggregate recomputes the aggregate for all runs in the given group.
+func (runs *RunGroup) updateAggregate(group string) {
+	if group == "" {
+		return
+	}
+
+	numRuns := 0
+	samples := make(map[string][]float64)
+	for _, run := range runs.Runs {
+		if run.Group != group {
+			continue
+		}
+		numRuns++
+		if run.Result == nil || !run.Result.Success {
+			continue
+		}
+		for name, value := range keyMetricsByName(run.Result) {
+			samples[name] = append(samples[name], value)
+		}
+	}
+
+	aggregate := &AggregateResult{
+		NumRuns:    numRuns,
+		Confidence: 0.95,
+		Metrics:    make(map[string]AggregateMetric),
+	}
+	for name, values := range samples {
+		aggregate.NumSuccessful = len(values)
+		mean, lower, upper := metrics.ConfidenceInterval95(values)
+		aggregate.Metrics[name] = AggregateMetric{
+			Mean:    mean,
+			StdDev:  metrics.StdDev(values),
+			CILower: lower,
+			CIUpper: upper,
+		}
+	}
+
+	for i := range runs.Runs {
+		if runs.Runs[i].Group == group {
+			runs.Runs[i].Aggregate = aggregate
+		}
+	}
 }
 
 // RunGroup is a group of runs that is meant to be compared.
@@ -38,6 +146,7 @@ func (runs *RunGroup) AddResult(testIdx int, runResult RunResult) {
 	}
 
 	runs.Runs[testIdx].Result = &runResult
+	runs.updateAggregate(runs.Runs[testIdx].Group)
 }
 
 const (
@@ -61,9 +170,54 @@ func RunGroupFromTestPlans(testPlans []TestPlan) RunGroup {
 				OutputDir:       params.OutputDir,
 				Thresholds:      testPlan.Thresholds,
 				CreatedAt:       &now,
+				Group:           params.Group,
+				Repetition:      params.Repetition,
 			})
 		}
 	}
 
 	return metadata
 }
+
+func sameTestConfig(a map[string]interface{}, b map[string]interface{}) bool {
+	// compare the JSON encoding since previous runs were decoded from JSON
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// ResumeFrom carries over the identity and successful results of previous
+// runs of the same benchmark run, so that only missing or failed runs need to
+// be executed again. The previous runs must have been created from the same
+// config.
+func (runs *RunGroup) ResumeFrom(previous []Run) error {
+	if len(previous) != len(runs.Runs) {
+		return fmt.Errorf("benchmark run has %d runs but config resolves to %d runs", len(previous), len(runs.Runs))
+	}
+
+	groups := make(map[string]bool)
+	for i := range runs.Runs {
+		run := &runs.Runs[i]
+		prev := previous[i]
+		if !sameTestConfig(run.TestConfig, prev.TestConfig) || run.Repetition != prev.Repetition {
+			return fmt.Errorf("run %d (%s) does not match the current config", i, prev.OutputDir)
+		}
+
+		run.ID = prev.ID
+		run.OutputDir = prev.OutputDir
+		run.Group = prev.Group
+		run.CreatedAt = prev.CreatedAt
+		if prev.Result != nil && prev.Result.Success {
+			run.Result = prev.Result
+			run.Resources = prev.Resources
+			run.Clients = prev.Clients
+			run.Host = prev.Host
+		}
+		groups[run.Group] = true
+	}
+
+	for group := range groups {
+		runs.updateAggregate(group)
+	}
+	return nil
+}
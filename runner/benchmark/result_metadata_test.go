@@ -0,0 +1,74 @@
+package benchmark_test
+
+import (
+	"testing"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGroup_AddResultAggregatesGroup(t *testing.T) {
+	runs := benchmark.RunGroup{
+		Runs: []benchmark.Run{
+			{OutputDir: "a-0-0", Group: "a-0"},
+			{OutputDir: "a-0-1", Group: "a-0", Repetition: 1},
+			{OutputDir: "a-0-2", Group: "a-0", Repetition: 2},
+			{OutputDir: "a-1"},
+		},
+	}
+
+	for i, latency := range []float64{1.0, 2.0} {
+		runs.AddResult(i, benchmark.RunResult{
+			Success:  true,
+			Complete: true,
+			ValidatorMetrics: types.ValidatorKeyMetrics{
+				AverageNewPayloadLatency: latency,
+			},
+		})
+	}
+	runs.AddResult(2, benchmark.RunResult{Success: false, Complete: true})
+
+	aggregate := runs.Runs[0].Aggregate
+	require.NotNil(t, aggregate)
+	require.Same(t, aggregate, runs.Runs[2].Aggregate)
+	require.Nil(t, runs.Runs[3].Aggregate)
+
+	require.Equal(t, 3, aggregate.NumRuns)
+	require.Equal(t, 2, aggregate.NumSuccessful)
+	metric := aggregate.Metrics["validator/latency/new_payload"]
+	require.InDelta(t, 1.5, metric.Mean, 1e-9)
+	require.Less(t, metric.CILower, 1.5)
+	require.Greater(t, metric.CIUpper, 1.5)
+}
+
+func TestRunGroup_ResumeFrom(t *testing.T) {
+	newGroup := func() benchmark.RunGroup {
+		return benchmark.RunGroup{
+			Runs: []benchmark.Run{
+				{ID: "new", OutputDir: "new-0", TestConfig: map[string]interface{}{"NodeType": "geth", "GasLimit": uint64(30000000)}},
+				{ID: "new", OutputDir: "new-1", TestConfig: map[string]interface{}{"NodeType": "reth", "GasLimit": uint64(30000000)}},
+			},
+		}
+	}
+
+	// previous runs are decoded from metadata.json so numbers are floats
+	previous := []benchmark.Run{
+		{ID: "old", OutputDir: "old-0", TestConfig: map[string]interface{}{"NodeType": "geth", "GasLimit": float64(30000000)}, Result: &benchmark.RunResult{Success: true, Complete: true}},
+		{ID: "old", OutputDir: "old-1", TestConfig: map[string]interface{}{"NodeType": "reth", "GasLimit": float64(30000000)}, Result: &benchmark.RunResult{Success: false, Complete: true}},
+	}
+
+	runs := newGroup()
+	require.NoError(t, runs.ResumeFrom(previous))
+	require.Equal(t, "old-0", runs.Runs[0].OutputDir)
+	require.Equal(t, "old-1", runs.Runs[1].OutputDir)
+	require.NotNil(t, runs.Runs[0].Result)
+	require.Nil(t, runs.Runs[1].Result, "failed runs should be executed again")
+
+	runs = newGroup()
+	require.Error(t, runs.ResumeFrom(previous[:1]))
+
+	runs = newGroup()
+	runs.Runs[1].TestConfig["NodeType"] = "rbuilder"
+	require.Error(t, runs.ResumeFrom(previous))
+}
@@ -0,0 +1,211 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+)
+
+// ThresholdLevel is the severity of a threshold breach.
+type ThresholdLevel string
+
+const (
+	ThresholdLevelWarning ThresholdLevel = "warning"
+	ThresholdLevelError   ThresholdLevel = "error"
+)
+
+// ThresholdScope describes which value was compared against a threshold.
+type ThresholdScope string
+
+const (
+	// ThresholdScopeSummary means the run-level key metric breached the threshold.
+	ThresholdScopeSummary ThresholdScope = "summary"
+	// ThresholdScopeBlock means one or more individual blocks breached the threshold.
+	ThresholdScopeBlock ThresholdScope = "block"
+)
+
+const (
+	SequencerRole = types.SequencerRole
+	ValidatorRole = types.ValidatorRole
+)
+
+// ThresholdBreach records a single metric that breached a configured threshold.
+// Values use the same units as metrics-*.json (nanoseconds for latencies).
+type ThresholdBreach struct {
+	Level     ThresholdLevel `json:"level"`
+	Scope     ThresholdScope `json:"scope"`
+	Role      string         `json:"role"`
+	Metric    string         `json:"metric"`
+	Threshold float64        `json:"threshold"`
+	// Value is the offending value. For block scope this is the worst block.
+	Value float64 `json:"value"`
+	// BlockNumber is the worst offending block for block scope.
+	BlockNumber *uint64 `json:"blockNumber,omitempty"`
+	// NumBlocks is the number of blocks that exceeded the threshold.
+	NumBlocks int `json:"numBlocks,omitempty"`
+}
+
+func (b ThresholdBreach) String() string {
+	verb := "exceeded"
+	if higherIsBetter(b.Metric) {
+		verb = "fell below"
+	}
+	if b.Scope == ThresholdScopeBlock && b.BlockNumber != nil {
+		return fmt.Sprintf("%s %s/%s: %d blocks %s %g (worst %g at block %d)", b.Level, b.Role, b.Metric, b.NumBlocks, verb, b.Threshold, b.Value, *b.BlockNumber)
+	}
+	return fmt.Sprintf("%s %s/%s: %g %s %g", b.Level, b.Role, b.Metric, b.Value, verb, b.Threshold)
+}
+
+// higherIsBetterMetrics are the throughput metrics. Their thresholds are
+// minimums, breached by values below the threshold.
+var higherIsBetterMetrics = map[string]bool{
+	types.GasPerSecondMetric: true,
+}
+
+func higherIsBetter(metric string) bool {
+	return higherIsBetterMetrics[metric]
+}
+
+// worse returns whether value is worse than other for the given metric.
+func worse(metric string, value float64, other float64) bool {
+	if higherIsBetter(metric) {
+		return value < other
+	}
+	return value > other
+}
+
+// keyMetricValue returns the run-level key metric for the given role and
+// metric name, scaled to the units used by the per-block metrics.
+func keyMetricValue(result *RunResult, role string, metric string) (float64, bool) {
+	// key metrics store latencies in seconds, thresholds use nanoseconds
+	const secondsToNanos = float64(time.Second)
+
+	switch role {
+	case SequencerRole:
+		switch metric {
+		case types.UpdateForkChoiceLatencyMetric:
+			return result.SequencerMetrics.AverageFCULatency * secondsToNanos, true
+		case types.GetPayloadLatencyMetric:
+			return result.SequencerMetrics.AverageGetPayloadLatency * secondsToNanos, true
+		case types.SendTxsLatencyMetric:
+			return result.SequencerMetrics.AverageSendTxsLatency * secondsToNanos, true
+		case types.GasPerSecondMetric:
+			return result.SequencerMetrics.AverageGasPerSecond, true
+		}
+	case ValidatorRole:
+		switch metric {
+		case types.NewPayloadLatencyMetric:
+			return result.ValidatorMetrics.AverageNewPayloadLatency * secondsToNanos, true
+		case types.GasPerSecondMetric:
+			return result.ValidatorMetrics.AverageGasPerSecond, true
+		}
+	}
+	return 0, false
+}
+
+// blockMetricValue returns the value of a metric for a single block in the
+// units written to metrics-*.json.
+func blockMetricValue(m metrics.BlockMetrics, metric string) (float64, bool) {
+	if d, ok := m.ExecutionMetrics[metric].(time.Duration); ok {
+		return float64(d.Nanoseconds()), true
+	}
+	return m.GetMetricFloat(metric)
+}
+
+// parseThresholdKey splits a threshold key of the form "<role>/<metric>" into
+// its role and metric. Keys without a role prefix apply to both roles.
+func parseThresholdKey(key string) ([]string, string) {
+	for _, role := range []string{SequencerRole, ValidatorRole} {
+		if metric, ok := strings.CutPrefix(key, role+"/"); ok {
+			return []string{role}, metric
+		}
+	}
+	return []string{SequencerRole, ValidatorRole}, key
+}
+
+func checkThresholdLevel(level ThresholdLevel, thresholds map[string]float64, result *RunResult, blockMetrics map[string][]metrics.BlockMetrics) []ThresholdBreach {
+	// iterate in a stable order so breaches are reported deterministically
+	keys := make([]string, 0, len(thresholds))
+	for k := range thresholds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	breaches := make([]ThresholdBreach, 0)
+	for _, key := range keys {
+		threshold := thresholds[key]
+		roles, metric := parseThresholdKey(key)
+
+		for _, role := range roles {
+			if value, ok := keyMetricValue(result, role, metric); ok && worse(metric, value, threshold) {
+				breaches = append(breaches, ThresholdBreach{
+					Level:     level,
+					Scope:     ThresholdScopeSummary,
+					Role:      role,
+					Metric:    metric,
+					Threshold: threshold,
+					Value:     value,
+				})
+			}
+
+			var worst *ThresholdBreach
+			for _, m := range blockMetrics[role] {
+				value, ok := blockMetricValue(m, metric)
+				if !ok || !worse(metric, value, threshold) {
+					continue
+				}
+				if worst == nil {
+					worst = &ThresholdBreach{
+						Level:     level,
+						Scope:     ThresholdScopeBlock,
+						Role:      role,
+						Metric:    metric,
+						Threshold: threshold,
+						Value:     value,
+					}
+				}
+				worst.NumBlocks++
+				if !worse(metric, worst.Value, value) {
+					blockNumber := m.BlockNumber
+					worst.Value = value
+					worst.BlockNumber = &blockNumber
+				}
+			}
+			if worst != nil {
+				breaches = append(breaches, *worst)
+			}
+		}
+	}
+	return breaches
+}
+
+// CheckThresholds compares the key metrics and per-block metrics of a run
+// against the warning and error thresholds and returns any breaches.
+func (c *ThresholdConfig) CheckThresholds(result *RunResult, sequencerMetrics []metrics.BlockMetrics, validatorMetrics []metrics.BlockMetrics) []ThresholdBreach {
+	if c == nil || result == nil {
+		return nil
+	}
+
+	blockMetrics := map[string][]metrics.BlockMetrics{
+		SequencerRole: sequencerMetrics,
+		ValidatorRole: validatorMetrics,
+	}
+
+	breaches := checkThresholdLevel(ThresholdLevelError, c.Error, result, blockMetrics)
+	breaches = append(breaches, checkThresholdLevel(ThresholdLevelWarning, c.Warning, result, blockMetrics)...)
+	return breaches
+}
+
+// HasErrorBreach returns true if any error-level threshold was exceeded.
+func (r *RunResult) HasErrorBreach() bool {
+	for _, b := range r.ThresholdBreaches {
+		if b.Level == ThresholdLevelError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package benchmark_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/stretchr/testify/require"
+)
+
+func blockWithMetric(blockNumber uint64, name string, value interface{}) metrics.BlockMetrics {
+	m := metrics.NewBlockMetrics()
+	m.SetBlockNumber(blockNumber)
+	m.AddExecutionMetric(name, value)
+	return *m
+}
+
+func TestThresholdConfig_CheckThresholds(t *testing.T) {
+	result := &benchmark.RunResult{
+		Success:  true,
+		Complete: true,
+		SequencerMetrics: types.SequencerKeyMetrics{
+			AverageGetPayloadLatency: 0.5,
+		},
+		ValidatorMetrics: types.ValidatorKeyMetrics{
+			AverageNewPayloadLatency: 1.2,
+		},
+	}
+
+	sequencerBlocks := []metrics.BlockMetrics{
+		blockWithMetric(0, types.GetPayloadLatencyMetric, 200*time.Millisecond),
+		blockWithMetric(1, types.GetPayloadLatencyMetric, 1100*time.Millisecond),
+		blockWithMetric(2, types.GetPayloadLatencyMetric, 200*time.Millisecond),
+	}
+	validatorBlocks := []metrics.BlockMetrics{
+		blockWithMetric(0, types.NewPayloadLatencyMetric, 1200*time.Millisecond),
+		blockWithMetric(1, types.NewPayloadLatencyMetric, 1300*time.Millisecond),
+	}
+
+	t.Run("nil config has no breaches", func(t *testing.T) {
+		var cfg *benchmark.ThresholdConfig
+		require.Empty(t, cfg.CheckThresholds(result, sequencerBlocks, validatorBlocks))
+	})
+
+	t.Run("role prefixed keys", func(t *testing.T) {
+		cfg := &benchmark.ThresholdConfig{
+			Warning: map[string]float64{
+				"sequencer/latency/get_payload": 1e9,
+			},
+			Error: map[string]float64{
+				"validator/latency/new_payload": 1.5e9,
+			},
+		}
+
+		breaches := cfg.CheckThresholds(result, sequencerBlocks, validatorBlocks)
+		require.Len(t, breaches, 1)
+		require.Equal(t, benchmark.ThresholdLevelWarning, breaches[0].Level)
+		require.Equal(t, benchmark.ThresholdScopeBlock, breaches[0].Scope)
+		require.Equal(t, benchmark.SequencerRole, breaches[0].Role)
+		require.Equal(t, 1, breaches[0].NumBlocks)
+		require.Equal(t, uint64(1), *breaches[0].BlockNumber)
+		require.Equal(t, float64(1100*time.Millisecond), breaches[0].Value)
+
+		res := *result
+		res.ThresholdBreaches = breaches
+		require.False(t, res.HasErrorBreach())
+	})
+
+	t.Run("summary and block breaches", func(t *testing.T) {
+		cfg := &benchmark.ThresholdConfig{
+			Error: map[string]float64{
+				"latency/new_payload": 1e9,
+			},
+		}
+
+		breaches := cfg.CheckThresholds(result, sequencerBlocks, validatorBlocks)
+		require.Len(t, breaches, 2)
+		require.Equal(t, benchmark.ThresholdScopeSummary, breaches[0].Scope)
+		require.InDelta(t, 1.2e9, breaches[0].Value, 1)
+		require.Equal(t, benchmark.ThresholdScopeBlock, breaches[1].Scope)
+		require.Equal(t, 2, breaches[1].NumBlocks)
+		require.Equal(t, uint64(1), *breaches[1].BlockNumber)
+
+		res := *result
+		res.ThresholdBreaches = breaches
+		require.True(t, res.HasErrorBreach())
+	})
+
+	t.Run("gas per second is a minimum", func(t *testing.T) {
+		throughput := *result
+		throughput.ValidatorMetrics.AverageGasPerSecond = 150e6
+		validatorBlocks := []metrics.BlockMetrics{
+			blockWithMetric(0, types.GasPerSecondMetric, 250e6),
+			blockWithMetric(1, types.GasPerSecondMetric, 80e6),
+			blockWithMetric(2, types.GasPerSecondMetric, 90e6),
+			blockWithMetric(3, types.GasPerSecondMetric, 300e6),
+		}
+
+		cfg := &benchmark.ThresholdConfig{
+			Warning: map[string]float64{
+				"validator/gas/per_second": 100e6,
+			},
+			Error: map[string]float64{
+				"validator/gas/per_second": 200e6,
+			},
+		}
+
+		breaches := cfg.CheckThresholds(&throughput, nil, validatorBlocks)
+		require.Len(t, breaches, 3)
+
+		require.Equal(t, benchmark.ThresholdLevelError, breaches[0].Level)
+		require.Equal(t, benchmark.ThresholdScopeSummary, breaches[0].Scope)
+		require.Equal(t, 150e6, breaches[0].Value)
+
+		require.Equal(t, benchmark.ThresholdLevelError, breaches[1].Level)
+		require.Equal(t, benchmark.ThresholdScopeBlock, breaches[1].Scope)
+		require.Equal(t, 2, breaches[1].NumBlocks)
+		require.Equal(t, uint64(1), *breaches[1].BlockNumber)
+		require.Equal(t, 80e6, breaches[1].Value)
+
+		require.Equal(t, benchmark.ThresholdLevelWarning, breaches[2].Level)
+		require.Equal(t, benchmark.ThresholdScopeBlock, breaches[2].Scope)
+		require.Equal(t, 2, breaches[2].NumBlocks)
+		require.Equal(t, uint64(1), *breaches[2].BlockNumber)
+		require.Contains(t, breaches[2].String(), "fell below")
+
+		fast := &benchmark.ThresholdConfig{
+			Error: map[string]float64{
+				"validator/gas/per_second": 50e6,
+			},
+		}
+		require.Empty(t, fast.CheckThresholds(&throughput, nil, validatorBlocks))
+	})
+}
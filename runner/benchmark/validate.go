@@ -0,0 +1,360 @@
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/base/base-bench/runner/payload"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a problem found while validating a benchmark config file.
+type ConfigError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ConfigErrors is a list of problems found in a benchmark config file.
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+var (
+	definitionType     = reflect.TypeOf(payload.Definition{})
+	paramType          = reflect.TypeOf(Param{})
+	matrixRuleType     = reflect.TypeOf(MatrixRule{})
+	testDefinitionType = reflect.TypeOf(TestDefinition{})
+)
+
+// payloadDefinitionKeys are the keys shared by all payload definitions.
+var payloadDefinitionKeys = []string{"name", "id", "type"}
+
+// ReadBenchmarkConfig reads a benchmark config file and rejects unknown keys,
+// unknown variable types and references to undefined payloads. Errors are
+// reported with the line number they occur on.
+func ReadBenchmarkConfig(path string) (*BenchmarkConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	if errs := ValidateConfigNode(&root); len(errs) > 0 {
+		return nil, errs
+	}
+
+	config := &BenchmarkConfig{}
+	if err := root.Decode(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ValidateConfigNode checks a parsed benchmark config against the schema of
+// BenchmarkConfig.
+func ValidateConfigNode(root *yaml.Node) ConfigErrors {
+	v := &configValidator{
+		payloadIDs: make(map[string]bool),
+	}
+
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+
+	v.collectPayloadIDs(node)
+	v.checkNode(node, reflect.TypeOf(BenchmarkConfig{}))
+	return v.errs
+}
+
+type configValidator struct {
+	payloadIDs map[string]bool
+	errs       ConfigErrors
+}
+
+func (v *configValidator) errorf(node *yaml.Node, format string, args ...interface{}) {
+	v.errs = append(v.errs, ConfigError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// mappingValue returns the value node for the given key of a mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func (v *configValidator) collectPayloadIDs(root *yaml.Node) {
+	payloads := mappingValue(root, "payloads")
+	if payloads == nil || payloads.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, p := range payloads.Content {
+		id := mappingValue(p, "id")
+		if id == nil {
+			v.errorf(p, "payload is missing an id")
+			continue
+		}
+		if v.payloadIDs[id.Value] {
+			v.errorf(id, "duplicate payload id %q", id.Value)
+		}
+		v.payloadIDs[id.Value] = true
+	}
+}
+
+// yamlFields returns the yaml keys of a struct type mapped to their field types.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func (v *configValidator) checkNode(node *yaml.Node, t reflect.Type) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case definitionType:
+		v.checkPayloadDefinition(node)
+		return
+	case matrixRuleType:
+		v.checkMatrixRule(node)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		v.checkMapping(node, t.String(), yamlFields(t))
+		if t == paramType {
+			v.checkParam(node)
+		}
+		if t == testDefinitionType {
+			v.checkExcludeRules(node)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, item := range node.Content {
+			v.checkNode(item, t.Elem())
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			v.checkNode(node.Content[i], t.Elem())
+		}
+	}
+}
+
+func (v *configValidator) checkMapping(node *yaml.Node, typeName string, fields map[string]reflect.Type) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		fieldType, ok := fields[key.Value]
+		if !ok {
+			v.errorf(key, "unknown field %q in %s%s", key.Value, typeName, suggestField(key.Value, fields))
+			continue
+		}
+		v.checkNode(node.Content[i+1], fieldType)
+	}
+}
+
+func (v *configValidator) checkPayloadDefinition(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	payloadType := mappingValue(node, "type")
+	if payloadType == nil {
+		v.errorf(node, "payload is missing a type")
+		return
+	}
+
+	params, err := payload.NewDefinitionParams(payloadType.Value)
+	if err != nil {
+		v.errorf(payloadType, "%v", err)
+		return
+	}
+
+	paramsType := reflect.TypeOf(params).Elem()
+	fields := yamlFields(paramsType)
+	for _, key := range payloadDefinitionKeys {
+		fields[key] = reflect.TypeOf("")
+	}
+	v.checkMapping(node, fmt.Sprintf("%s payload", payloadType.Value), fields)
+}
+
+func (v *configValidator) checkParam(node *yaml.Node) {
+	paramType := mappingValue(node, "type")
+	if paramType == nil {
+		v.errorf(node, "variable is missing a type")
+		return
+	}
+	if !paramTypes[paramType.Value] {
+		v.errorf(paramType, "unknown variable type %q", paramType.Value)
+		return
+	}
+
+	if paramType.Value != "payload" {
+		return
+	}
+	if value := mappingValue(node, "value"); value != nil {
+		v.checkPayloadReference(value)
+	}
+	if values := mappingValue(node, "values"); values != nil && values.Kind == yaml.SequenceNode {
+		for _, value := range values.Content {
+			v.checkPayloadReference(value)
+		}
+	}
+}
+
+func (v *configValidator) checkMatrixRule(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !paramTypes[key.Value] {
+			v.errorf(key, "unknown variable type %q", key.Value)
+			continue
+		}
+		if key.Value == "payload" {
+			v.checkPayloadReference(node.Content[i+1])
+		}
+	}
+}
+
+// checkExcludeRules rejects exclude rules referencing variables the benchmark
+// does not define, which would otherwise never match.
+func (v *configValidator) checkExcludeRules(node *yaml.Node) {
+	exclude := mappingValue(node, "exclude")
+	if exclude == nil || exclude.Kind != yaml.SequenceNode {
+		return
+	}
+
+	variables := make(map[string]bool)
+	if params := mappingValue(node, "variables"); params != nil && params.Kind == yaml.SequenceNode {
+		for _, param := range params.Content {
+			if paramType := mappingValue(param, "type"); paramType != nil {
+				variables[paramType.Value] = true
+			}
+		}
+	}
+
+	for _, rule := range exclude.Content {
+		if rule.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(rule.Content); i += 2 {
+			key := rule.Content[i]
+			// unknown variable types are reported by checkMatrixRule
+			if paramTypes[key.Value] && !variables[key.Value] {
+				v.errorf(key, "exclude rule references %s, which is not a variable of the benchmark", key.Value)
+			}
+		}
+	}
+}
+
+func (v *configValidator) checkPayloadReference(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+	if !v.payloadIDs[node.Value] {
+		v.errorf(node, "payload %q is not defined", node.Value)
+	}
+}
+
+// suggestField returns a hint naming the closest known field, if any is
+// close enough to likely be a typo.
+func suggestField(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best := ""
+	bestDistance := 3
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best = name
+			bestDistance = d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,112 @@
+package benchmark_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	configPath := path.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+	return configPath
+}
+
+func TestReadBenchmarkConfig(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		configPath := writeConfig(t, `name: test
+payloads:
+  - name: Simulator
+    id: simulator
+    type: simulator
+    accounts_loaded: 12
+    opcodes:
+      EXP: 10
+benchmarks:
+  - variables:
+      - type: payload
+        value: simulator
+      - type: node_type
+        values: [geth, reth]
+    exclude:
+      - node_type: reth
+`)
+		cfg, err := benchmark.ReadBenchmarkConfig(configPath)
+		require.NoError(t, err)
+		require.Len(t, cfg.Benchmarks, 1)
+		require.Len(t, cfg.TransactionPayloads, 1)
+	})
+
+	t.Run("reports errors with line numbers", func(t *testing.T) {
+		configPath := writeConfig(t, `name: test
+payloads:
+  - name: Simulator
+    id: simulator
+    type: simulator
+    account_loaded: 12
+benchmarks:
+  - snapshot:
+      command: ./setup.sh
+    force_clean: false
+    variables:
+      - type: payload
+        values: [simulator, missing]
+      - type: gas_limt
+        value: 100
+    include:
+      - node_typ: geth
+`)
+		_, err := benchmark.ReadBenchmarkConfig(configPath)
+		require.Error(t, err)
+
+		var errs benchmark.ConfigErrors
+		require.ErrorAs(t, err, &errs)
+
+		lines := make([]int, len(errs))
+		for i, e := range errs {
+			lines[i] = e.Line
+		}
+		require.Equal(t, []int{6, 10, 13, 14, 17}, lines)
+		require.Contains(t, errs[0].Message, `did you mean "accounts_loaded"?`)
+		require.Contains(t, errs[2].Message, `payload "missing" is not defined`)
+	})
+	t.Run("rejects exclude rules on undefined variables", func(t *testing.T) {
+		configPath := writeConfig(t, `name: test
+benchmarks:
+  - variables:
+      - type: node_type
+        values: [geth, reth]
+    exclude:
+      - node_type: reth
+        block_time: 2s
+`)
+		_, err := benchmark.ReadBenchmarkConfig(configPath)
+		var errs benchmark.ConfigErrors
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Equal(t, 8, errs[0].Line)
+		require.Contains(t, errs[0].Message, "block_time, which is not a variable of the benchmark")
+	})
+	t.Run("checks client metrics", func(t *testing.T) {
+		configPath := writeConfig(t, `name: test
+benchmarks:
+  - variables:
+      - type: node_type
+        value: reth
+    client_metrics:
+      reth:
+        - name: reth_sync_execution_execution_duration
+          labls:
+            quantile: "0.5"
+`)
+		_, err := benchmark.ReadBenchmarkConfig(configPath)
+		var errs benchmark.ConfigErrors
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Equal(t, 9, errs[0].Line)
+		require.Contains(t, errs[0].Message, `did you mean "labels"?`)
+	})
+}
@@ -0,0 +1,20 @@
+package common
+
+import (
+	"os/exec"
+)
+
+// TasksetBin is the binary used to pin client processes to a CPU set.
+const TasksetBin = "taskset"
+
+// NewCommand creates a command for a client binary. If cpuSet is set, the
+// process is started through taskset so that it and all of its threads are
+// pinned to the given CPU list (e.g. "0-3").
+func NewCommand(bin string, args []string, cpuSet string) *exec.Cmd {
+	if cpuSet == "" {
+		return exec.Command(bin, args...)
+	}
+
+	tasksetArgs := append([]string{"--cpu-list", cpuSet, bin}, args...)
+	return exec.Command(TasksetBin, tasksetArgs...)
+}
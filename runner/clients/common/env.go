@@ -0,0 +1,53 @@
+package common
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// baseEnvVars are the variables inherited from the runner's environment by
+// client processes. Everything else, such as GOGC or RUST_LOG set in the
+// shell, is dropped so that only the run's env params affect the clients.
+var baseEnvVars = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"LANG",
+	"LC_ALL",
+	"TMPDIR",
+	"TZ",
+}
+
+// BaseEnv returns the sanitized environment client processes start from.
+func BaseEnv() []string {
+	env := make([]string, 0, len(baseEnvVars))
+	for _, key := range baseEnvVars {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// MergeEnv returns the base environment extended with the given variables in
+// sorted order. Variables in env override the base environment.
+func MergeEnv(base []string, env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	merged := make([]string, 0, len(base)+len(keys))
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := env[key]; !ok {
+			merged = append(merged, entry)
+		}
+	}
+	for _, key := range keys {
+		merged = append(merged, key+"="+env[key])
+	}
+	return merged
+}
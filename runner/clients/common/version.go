@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/base/base-bench/runner/clients/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// GetClientVersion fingerprints a client binary by its --version output, its
+// path and sha256, and the web3_clientVersion reported by the running client.
+// Failures are logged and leave the corresponding fields empty.
+func GetClientVersion(ctx context.Context, logger log.Logger, bin string, client *ethclient.Client) *types.ClientVersion {
+	version := &types.ClientVersion{}
+
+	binPath, err := exec.LookPath(bin)
+	if err == nil {
+		binPath, err = filepath.Abs(binPath)
+	}
+	if err != nil {
+		logger.Warn("failed to resolve client binary", "bin", bin, "err", err)
+	} else {
+		version.BinaryPath = binPath
+		version.BinarySHA256, err = fileSHA256(binPath)
+		if err != nil {
+			logger.Warn("failed to hash client binary", "path", binPath, "err", err)
+		}
+	}
+
+	output, err := exec.CommandContext(ctx, bin, "--version").Output()
+	if err != nil {
+		logger.Warn("failed to get client version", "bin", bin, "err", err)
+	} else {
+		version.Version = strings.TrimSpace(string(output))
+	}
+
+	if client != nil {
+		err = client.Client().CallContext(ctx, &version.ClientVersion, "web3_clientVersion")
+		if err != nil {
+			logger.Warn("failed to get web3_clientVersion", "err", err)
+		}
+	}
+
+	return version
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
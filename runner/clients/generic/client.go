@@ -0,0 +1,273 @@
+package generic
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/base/base-bench/runner/benchmark/portmanager"
+	"github.com/base/base-bench/runner/clients/common"
+	genericoptions "github.com/base/base-bench/runner/clients/generic/options"
+	"github.com/base/base-bench/runner/clients/types"
+	"github.com/base/base-bench/runner/config"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// GenericClient handles the lifecycle of a client described by a client
+// descriptor.
+type GenericClient struct {
+	logger     log.Logger
+	options    *config.InternalClientOptions
+	descriptor *genericoptions.ClientDescriptor
+
+	client     *ethclient.Client
+	clientURL  string
+	authClient client.RPC
+	process    *exec.Cmd
+
+	ports       portmanager.PortManager
+	metricsPort uint64
+	rpcPort     uint64
+	authRPCPort uint64
+
+	stdout io.WriteCloser
+	stderr io.WriteCloser
+
+	metricsScraper metrics.Scraper
+}
+
+// NewGenericClient creates a new client for the given client descriptor.
+func NewGenericClient(logger log.Logger, options *config.InternalClientOptions, ports portmanager.PortManager, descriptor *genericoptions.ClientDescriptor) types.ExecutionClient {
+	return &GenericClient{
+		logger:     logger,
+		options:    options,
+		ports:      ports,
+		descriptor: descriptor,
+	}
+}
+
+func (g *GenericClient) MetricsScraper() metrics.Scraper {
+	return g.metricsScraper
+}
+
+// placeholderValues returns the values substituted into the descriptor's
+// templates.
+func (g *GenericClient) placeholderValues() map[string]string {
+	return map[string]string{
+		genericoptions.PlaceholderDataDir:       g.options.DataDirPath,
+		genericoptions.PlaceholderChainConfig:   g.options.ChainCfgPath,
+		genericoptions.PlaceholderJWTSecretPath: g.options.JWTSecretPath,
+		genericoptions.PlaceholderRPCPort:       fmt.Sprintf("%d", g.rpcPort),
+		genericoptions.PlaceholderAuthRPCPort:   fmt.Sprintf("%d", g.authRPCPort),
+		genericoptions.PlaceholderMetricsPort:   fmt.Sprintf("%d", g.metricsPort),
+	}
+}
+
+func expandArgs(templates []string, values map[string]string) []string {
+	args := make([]string, len(templates))
+	for i, template := range templates {
+		args[i] = genericoptions.Expand(template, values)
+	}
+	return args
+}
+
+// Run runs the client with the given runtime config.
+func (g *GenericClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
+	if g.stdout != nil {
+		_ = g.stdout.Close()
+	}
+
+	if g.stderr != nil {
+		_ = g.stderr.Close()
+	}
+
+	g.stdout = cfg.Stdout
+	g.stderr = cfg.Stderr
+
+	g.rpcPort = g.ports.AcquirePort(g.descriptor.Name, portmanager.ELPortPurpose)
+	g.authRPCPort = g.ports.AcquirePort(g.descriptor.Name, portmanager.AuthELPortPurpose)
+	g.metricsPort = g.ports.AcquirePort(g.descriptor.Name, portmanager.ELMetricsPortPurpose)
+
+	values := g.placeholderValues()
+
+	if g.descriptor.Init != nil && !g.options.SkipInit {
+		initArgs := expandArgs(g.descriptor.Init.Args, values)
+		g.logger.Debug("initializing client", "args", strings.Join(initArgs, " "))
+
+		cmd := exec.CommandContext(ctx, g.descriptor.Init.Binary, initArgs...)
+		cmd.Env = cfg.Env
+		cmd.Stdout = g.stdout
+		cmd.Stderr = g.stderr
+
+		err := cmd.Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to init %s", g.descriptor.Name)
+		}
+	}
+
+	args := expandArgs(g.descriptor.Args, values)
+	args = append(args, cfg.Args...)
+
+	jwtSecretStr, err := os.ReadFile(g.options.JWTSecretPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read jwt secret")
+	}
+
+	jwtSecretBytes, err := hex.DecodeString(string(jwtSecretStr))
+	if err != nil {
+		return err
+	}
+
+	if len(jwtSecretBytes) != 32 {
+		return errors.New("jwt secret must be 32 bytes")
+	}
+
+	jwtSecret := [32]byte{}
+	copy(jwtSecret[:], jwtSecretBytes[:])
+
+	g.logger.Debug("starting client", "args", strings.Join(args, " "))
+
+	g.process = common.NewCommand(g.descriptor.Binary, args, g.options.CPUSet)
+	g.process.Env = cfg.Env
+	g.process.Stdout = g.stdout
+	g.process.Stderr = g.stderr
+	err = g.process.Start()
+	if err != nil {
+		return err
+	}
+
+	g.clientURL = fmt.Sprintf("http://127.0.0.1:%d", g.rpcPort)
+	rpcClient, err := rpc.DialOptions(ctx, g.clientURL, rpc.WithHTTPClient(&http.Client{
+		Timeout: 30 * time.Second,
+	}))
+	if err != nil {
+		return errors.Wrap(err, "failed to dial rpc")
+	}
+
+	g.client = ethclient.NewClient(rpcClient)
+	selection := g.options.MetricsProfile.Selection(g.descriptor.Name, metrics.NewMetricSelection(g.descriptor.Metrics.Names...))
+	g.metricsScraper = newMetricsScraper(g.descriptor.Metrics, genericoptions.Expand(g.descriptor.Metrics.URL, values), selection)
+
+	err = g.waitForReadiness(ctx, values)
+	if err != nil {
+		return errors.Wrapf(err, "%s failed to start", g.descriptor.Name)
+	}
+
+	l2Node, err := client.NewRPC(ctx, g.logger, fmt.Sprintf("http://127.0.0.1:%d", g.authRPCPort), client.WithGethRPCOptions(rpc.WithHTTPAuth(node.NewJWTAuth(jwtSecret))), client.WithCallTimeout(240*time.Second))
+	if err != nil {
+		return err
+	}
+
+	g.authClient = l2Node
+
+	return nil
+}
+
+// waitForReadiness waits for the client to pass the descriptor's readiness
+// probe.
+func (g *GenericClient) waitForReadiness(ctx context.Context, values map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, g.descriptor.Readiness.Timeout)
+	defer cancel()
+
+	if g.descriptor.Readiness.Probe == genericoptions.ReadinessProbeRPC {
+		return common.WaitForRPC(ctx, g.client)
+	}
+
+	url := genericoptions.Expand(g.descriptor.Readiness.URL, values)
+	var lastErr error
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return nil
+			}
+			err = fmt.Errorf("readiness probe returned status %d", resp.StatusCode)
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(common.RetryInterval):
+		}
+	}
+}
+
+// Stop stops the client.
+func (g *GenericClient) Stop() {
+	if g.process == nil || g.process.Process == nil {
+		return
+	}
+	err := g.process.Process.Signal(os.Interrupt)
+	if err != nil {
+		g.logger.Error("failed to stop client", "err", err)
+	}
+
+	g.process.WaitDelay = 5 * time.Second
+
+	err = g.process.Wait()
+	if err != nil {
+		g.logger.Error("failed to wait for client", "err", err)
+	}
+
+	_ = g.stdout.Close()
+	_ = g.stderr.Close()
+
+	g.ports.ReleasePort(g.rpcPort)
+	g.ports.ReleasePort(g.authRPCPort)
+	g.ports.ReleasePort(g.metricsPort)
+
+	g.stdout = nil
+	g.stderr = nil
+	g.process = nil
+}
+
+// Client returns the ethclient client.
+func (g *GenericClient) Client() *ethclient.Client {
+	return g.client
+}
+
+// ClientURL returns the raw client URL for transaction generators.
+func (g *GenericClient) ClientURL() string {
+	return g.clientURL
+}
+
+// AuthClient returns the auth client used for CL communication.
+func (g *GenericClient) AuthClient() client.RPC {
+	return g.authClient
+}
+
+func (g *GenericClient) MetricsPort() int {
+	return int(g.metricsPort)
+}
+
+// Version returns the version of the client binary.
+func (g *GenericClient) Version(ctx context.Context) *types.ClientVersion {
+	return common.GetClientVersion(ctx, g.logger, g.descriptor.Binary, g.client)
+}
+
+// PID returns the process ID of the running client.
+func (g *GenericClient) PID() int {
+	if g.process == nil || g.process.Process == nil {
+		return 0
+	}
+	return g.process.Process.Pid
+}
@@ -0,0 +1,13 @@
+package generic
+
+import (
+	genericoptions "github.com/base/base-bench/runner/clients/generic/options"
+	"github.com/base/base-bench/runner/metrics"
+)
+
+func newMetricsScraper(endpoint genericoptions.MetricsEndpoint, url string, selection metrics.MetricSelection) metrics.Scraper {
+	if endpoint.Format == genericoptions.MetricsFormatJSON {
+		return metrics.NewJSONScraper(url, selection)
+	}
+	return metrics.NewPrometheusScraper(url, selection)
+}
@@ -0,0 +1,216 @@
+package options
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GenericOptions contains the descriptors of clients that are run without
+// client-specific code.
+type GenericOptions struct {
+	// ClientDescriptorPaths are the paths of the client descriptor files.
+	ClientDescriptorPaths []string
+	// ClientDescriptors are the loaded client descriptors keyed by node type.
+	ClientDescriptors map[string]*ClientDescriptor
+}
+
+// Metrics endpoint formats.
+const (
+	MetricsFormatPrometheus = "prometheus"
+	MetricsFormatJSON       = "json"
+)
+
+// Readiness probes.
+const (
+	ReadinessProbeRPC  = "rpc"
+	ReadinessProbeHTTP = "http"
+)
+
+// Placeholders that are substituted in the argv templates, metrics URL and
+// readiness URL of a client descriptor, written as ${name}.
+const (
+	PlaceholderDataDir       = "datadir"
+	PlaceholderChainConfig   = "chain_config"
+	PlaceholderJWTSecretPath = "jwt_secret_path"
+	PlaceholderRPCPort       = "rpc_port"
+	PlaceholderAuthRPCPort   = "auth_rpc_port"
+	PlaceholderMetricsPort   = "metrics_port"
+)
+
+var placeholders = map[string]bool{
+	PlaceholderDataDir:       true,
+	PlaceholderChainConfig:   true,
+	PlaceholderJWTSecretPath: true,
+	PlaceholderRPCPort:       true,
+	PlaceholderAuthRPCPort:   true,
+	PlaceholderMetricsPort:   true,
+}
+
+// DefaultReadinessTimeout is how long a client has to pass its readiness probe.
+const DefaultReadinessTimeout = 240 * time.Second
+
+// ClientDescriptor describes how to run an execution client binary.
+type ClientDescriptor struct {
+	// Name is the node type that selects this client in benchmark configs.
+	Name string `yaml:"name"`
+	// Binary is the client binary. Relative paths containing a separator are
+	// resolved against the directory of the descriptor file.
+	Binary string `yaml:"binary"`
+	// Init is run before the client on a fresh datadir. It is skipped when
+	// the datadir comes from a snapshot.
+	Init *InitCommand `yaml:"init"`
+	// Args is the argv template of the client.
+	Args      []string        `yaml:"args"`
+	Metrics   MetricsEndpoint `yaml:"metrics"`
+	Readiness ReadinessProbe  `yaml:"readiness"`
+}
+
+// InitCommand is a command that initializes the client's datadir.
+type InitCommand struct {
+	// Binary defaults to the client binary.
+	Binary string   `yaml:"binary"`
+	Args   []string `yaml:"args"`
+}
+
+// MetricsEndpoint describes where and in which format the client exposes
+// metrics.
+type MetricsEndpoint struct {
+	// URL defaults to http://127.0.0.1:${metrics_port}/metrics.
+	URL string `yaml:"url"`
+	// Format is either "prometheus" (default) or "json", a flat object of
+	// metric names to values.
+	Format string `yaml:"format"`
+	// Names are the metrics collected after every block.
+	Names []string `yaml:"names"`
+}
+
+// ReadinessProbe describes how to check that the client has started.
+type ReadinessProbe struct {
+	// Probe is either "rpc" (default), which waits for eth_blockNumber to
+	// succeed, or "http", which waits for URL to return a 2xx status.
+	Probe   string        `yaml:"probe"`
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// LoadClientDescriptor reads a client descriptor file, applies defaults and
+// checks it for errors.
+func LoadClientDescriptor(path string) (*ClientDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client descriptor: %w", err)
+	}
+
+	descriptor := &ClientDescriptor{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(descriptor); err != nil {
+		return nil, fmt.Errorf("failed to parse client descriptor %s: %w", path, err)
+	}
+
+	descriptor.Binary = resolveBinary(path, descriptor.Binary)
+	if descriptor.Init != nil {
+		descriptor.Init.Binary = resolveBinary(path, descriptor.Init.Binary)
+		if descriptor.Init.Binary == "" {
+			descriptor.Init.Binary = descriptor.Binary
+		}
+	}
+	if descriptor.Metrics.URL == "" {
+		descriptor.Metrics.URL = fmt.Sprintf("http://127.0.0.1:${%s}/metrics", PlaceholderMetricsPort)
+	}
+	if descriptor.Metrics.Format == "" {
+		descriptor.Metrics.Format = MetricsFormatPrometheus
+	}
+	if descriptor.Readiness.Probe == "" {
+		descriptor.Readiness.Probe = ReadinessProbeRPC
+	}
+	if descriptor.Readiness.Timeout == 0 {
+		descriptor.Readiness.Timeout = DefaultReadinessTimeout
+	}
+
+	if err := descriptor.Check(); err != nil {
+		return nil, fmt.Errorf("invalid client descriptor %s: %w", path, err)
+	}
+	return descriptor, nil
+}
+
+// resolveBinary resolves a relative binary path against the directory of the
+// descriptor file. Bare names are left to be looked up in PATH.
+func resolveBinary(descriptorPath string, binary string) string {
+	if binary == "" || filepath.IsAbs(binary) || !strings.ContainsRune(binary, filepath.Separator) {
+		return binary
+	}
+	return filepath.Join(filepath.Dir(descriptorPath), binary)
+}
+
+// Check validates the descriptor.
+func (d *ClientDescriptor) Check() error {
+	if d.Name == "" {
+		return errors.New("name is required")
+	}
+	if d.Binary == "" {
+		return errors.New("binary is required")
+	}
+	if len(d.Args) == 0 {
+		return errors.New("args are required")
+	}
+
+	templates := append([]string{d.Metrics.URL, d.Readiness.URL}, d.Args...)
+	if d.Init != nil {
+		if len(d.Init.Args) == 0 {
+			return errors.New("init args are required")
+		}
+		templates = append(templates, d.Init.Args...)
+	}
+	for _, template := range templates {
+		if err := checkPlaceholders(template); err != nil {
+			return err
+		}
+	}
+
+	switch d.Metrics.Format {
+	case MetricsFormatPrometheus, MetricsFormatJSON:
+	default:
+		return fmt.Errorf("unknown metrics format %q", d.Metrics.Format)
+	}
+
+	switch d.Readiness.Probe {
+	case ReadinessProbeRPC:
+	case ReadinessProbeHTTP:
+		if d.Readiness.URL == "" {
+			return errors.New("readiness url is required for the http probe")
+		}
+	default:
+		return fmt.Errorf("unknown readiness probe %q", d.Readiness.Probe)
+	}
+
+	return nil
+}
+
+func checkPlaceholders(template string) error {
+	var err error
+	os.Expand(template, func(name string) string {
+		if name != "$" && !placeholders[name] && err == nil {
+			err = fmt.Errorf("unknown placeholder ${%s} in %q", name, template)
+		}
+		return ""
+	})
+	return err
+}
+
+// Expand substitutes the placeholders in a template. "$$" expands to "$".
+func Expand(template string, values map[string]string) string {
+	return os.Expand(template, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return values[name]
+	})
+}
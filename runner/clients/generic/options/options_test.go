@@ -0,0 +1,159 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeDescriptor(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "client.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadClientDescriptor(t *testing.T) {
+	path := writeDescriptor(t, `name: nethermind
+binary: ./bin/nethermind
+init:
+  args: [init, --datadir, "${datadir}"]
+args: [--datadir, "${datadir}", --rpc-port, "${rpc_port}"]
+`)
+	descriptor, err := LoadClientDescriptor(path)
+	require.NoError(t, err)
+
+	binary := filepath.Join(filepath.Dir(path), "bin", "nethermind")
+	require.Equal(t, "nethermind", descriptor.Name)
+	require.Equal(t, binary, descriptor.Binary)
+	require.Equal(t, binary, descriptor.Init.Binary)
+	require.Equal(t, "http://127.0.0.1:${metrics_port}/metrics", descriptor.Metrics.URL)
+	require.Equal(t, MetricsFormatPrometheus, descriptor.Metrics.Format)
+	require.Equal(t, ReadinessProbeRPC, descriptor.Readiness.Probe)
+	require.Equal(t, DefaultReadinessTimeout, descriptor.Readiness.Timeout)
+}
+
+func TestLoadClientDescriptorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unknown field",
+			content: "name: client\nbinary: client\nargs: [run]\nbinnary: client\n",
+			err:     "field binnary not found",
+		},
+		{
+			name:    "missing name",
+			content: "binary: client\nargs: [run]\n",
+			err:     "name is required",
+		},
+		{
+			name:    "missing args",
+			content: "name: client\nbinary: client\n",
+			err:     "args are required",
+		},
+		{
+			name:    "missing init args",
+			content: "name: client\nbinary: client\nargs: [run]\ninit:\n  binary: client-init\n",
+			err:     "init args are required",
+		},
+		{
+			name:    "unknown placeholder",
+			content: "name: client\nbinary: client\nargs: [--port, \"${p2p_port}\"]\n",
+			err:     "unknown placeholder ${p2p_port}",
+		},
+		{
+			name:    "unknown placeholder in init args",
+			content: "name: client\nbinary: client\nargs: [run]\ninit:\n  args: [\"${genesis}\"]\n",
+			err:     "unknown placeholder ${genesis}",
+		},
+		{
+			name:    "http probe without url",
+			content: "name: client\nbinary: client\nargs: [run]\nreadiness:\n  probe: http\n",
+			err:     "readiness url is required for the http probe",
+		},
+		{
+			name:    "unknown probe",
+			content: "name: client\nbinary: client\nargs: [run]\nreadiness:\n  probe: tcp\n",
+			err:     "unknown readiness probe \"tcp\"",
+		},
+		{
+			name:    "unknown metrics format",
+			content: "name: client\nbinary: client\nargs: [run]\nmetrics:\n  format: xml\n",
+			err:     "unknown metrics format \"xml\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadClientDescriptor(writeDescriptor(t, tt.content))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	_, err := LoadClientDescriptor(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestResolveBinary(t *testing.T) {
+	descriptorPath := filepath.Join("/etc", "clients", "client.yaml")
+
+	tests := []struct {
+		binary string
+		want   string
+	}{
+		{binary: "", want: ""},
+		{binary: "nethermind", want: "nethermind"},
+		{binary: "/usr/bin/nethermind", want: "/usr/bin/nethermind"},
+		{binary: "./bin/nethermind", want: "/etc/clients/bin/nethermind"},
+		{binary: "../nethermind/nethermind", want: "/etc/nethermind/nethermind"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, resolveBinary(descriptorPath, tt.binary), tt.binary)
+	}
+}
+
+func TestInitBinary(t *testing.T) {
+	path := writeDescriptor(t, `name: client
+binary: /usr/bin/client
+init:
+  binary: ./client-init
+  args: [init]
+args: [run]
+`)
+	descriptor, err := LoadClientDescriptor(path)
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/client", descriptor.Binary)
+	require.Equal(t, filepath.Join(filepath.Dir(path), "client-init"), descriptor.Init.Binary)
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	require.NoError(t, checkPlaceholders("--datadir=${datadir}"))
+	require.NoError(t, checkPlaceholders("$rpc_port"))
+	require.NoError(t, checkPlaceholders("price=$$5"))
+	require.ErrorContains(t, checkPlaceholders("${datadir}/${network}"), "unknown placeholder ${network}")
+}
+
+func TestExpand(t *testing.T) {
+	values := map[string]string{
+		PlaceholderDataDir: "/data",
+		PlaceholderRPCPort: "8545",
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "--datadir=${datadir}", want: "--datadir=/data"},
+		{template: "http://127.0.0.1:${rpc_port}", want: "http://127.0.0.1:8545"},
+		{template: "$datadir/db", want: "/data/db"},
+		{template: "price=$$5", want: "price=$5"},
+		{template: "--no-placeholders", want: "--no-placeholders"},
+		{template: "${metrics_port}", want: ""},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, Expand(tt.template, values), tt.template)
+	}
+}
@@ -43,7 +43,7 @@ type GethClient struct {
 	stdout io.WriteCloser
 	stderr io.WriteCloser
 
-	metricsCollector metrics.Collector
+	metricsScraper metrics.Scraper
 }
 
 // NewGethClient creates a new client for geth.
@@ -55,8 +55,8 @@ func NewGethClient(logger log.Logger, options *config.InternalClientOptions, por
 	}
 }
 
-func (g *GethClient) MetricsCollector() metrics.Collector {
-	return g.metricsCollector
+func (g *GethClient) MetricsScraper() metrics.Scraper {
+	return g.metricsScraper
 }
 
 // Run runs the geth client with the given runtime config.
@@ -82,6 +82,7 @@ func (g *GethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 		args = append(args, "init", g.options.ChainCfgPath)
 
 		cmd := exec.CommandContext(ctx, g.options.GethBin, args...)
+		cmd.Env = cfg.Env
 		cmd.Stdout = g.stdout
 		cmd.Stderr = g.stderr
 
@@ -115,7 +116,11 @@ func (g *GethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 	args = append(args, "--nodiscover")
 	args = append(args, "--rpc.txfeecap", "20")
 	args = append(args, "--syncmode", "full")
-	args = append(args, "--http.api", "eth,net,web3,miner,debug")
+	httpAPI := "eth,net,web3,miner"
+	if g.options.DebugAPI {
+		httpAPI += ",debug"
+	}
+	args = append(args, "--http.api", httpAPI)
 	args = append(args, "--gcmode", "archive")
 	args = append(args, "--authrpc.jwtsecret", g.options.JWTSecretPath)
 
@@ -125,6 +130,8 @@ func (g *GethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 	minerNewPayloadTimeout := time.Second * 2
 	args = append(args, "--miner.newpayload-timeout", minerNewPayloadTimeout.String())
 
+	args = append(args, cfg.Args...)
+
 	jwtSecretStr, err := os.ReadFile(g.options.JWTSecretPath)
 	if err != nil {
 		return errors.Wrap(err, "failed to read jwt secret")
@@ -144,7 +151,8 @@ func (g *GethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 
 	g.logger.Debug("starting geth", "args", strings.Join(args, " "))
 
-	g.process = exec.Command(g.options.GethBin, args...)
+	g.process = common.NewCommand(g.options.GethBin, args, g.options.CPUSet)
+	g.process.Env = cfg.Env
 	g.process.Stdout = g.stdout
 	g.process.Stderr = g.stderr
 	err = g.process.Start()
@@ -161,7 +169,7 @@ func (g *GethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 	}
 
 	g.client = ethclient.NewClient(rpcClient)
-	g.metricsCollector = newMetricsCollector(g.logger, g.client, int(g.metricsPort))
+	g.metricsScraper = newMetricsScraper(g.options, int(g.metricsPort))
 
 	err = common.WaitForRPC(ctx, g.client)
 	if err != nil {
@@ -225,3 +233,16 @@ func (g *GethClient) AuthClient() client.RPC {
 func (r *GethClient) MetricsPort() int {
 	return int(r.metricsPort)
 }
+
+// Version returns the version of the geth binary.
+func (g *GethClient) Version(ctx context.Context) *types.ClientVersion {
+	return common.GetClientVersion(ctx, g.logger, g.options.GethBin, g.client)
+}
+
+// PID returns the process ID of the running client.
+func (g *GethClient) PID() int {
+	if g.process == nil || g.process.Process == nil {
+		return 0
+	}
+	return g.process.Process.Pid
+}
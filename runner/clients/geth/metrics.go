@@ -1,83 +1,32 @@
 package geth
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 
+	"github.com/base/base-bench/runner/config"
 	"github.com/base/base-bench/runner/metrics"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/log"
 )
 
-type metricsCollector struct {
-	log         log.Logger
-	client      *ethclient.Client
-	metrics     []metrics.BlockMetrics
-	metricsPort int
-}
-
-func newMetricsCollector(log log.Logger, client *ethclient.Client, metricsPort int) metrics.Collector {
-	return &metricsCollector{
-		log:         log,
-		client:      client,
-		metricsPort: metricsPort,
-		metrics:     make([]metrics.BlockMetrics, 0),
-	}
-}
-
-func (g *metricsCollector) GetMetricTypes() map[string]bool {
-	return map[string]bool{
-		"chain/account/reads.50-percentile":    true,
-		"chain/execution.50-percentile":        true,
-		"chain/crossvalidation.50-percentile":  true,
-		"chain/storage/reads.50-percentile":    true,
-		"chain/account/updates.50-percentile":  true,
-		"chain/account/hashes.50-percentile":   true,
-		"chain/storage/updates.50-percentile":  true,
-		"chain/validation.50-percentile":       true,
-		"chain/write.50-percentile":            true,
-		"chain/snapshot/commits.50-percentile": true,
-		"chain/triedb/commits.50-percentile":   true,
-		"chain/account/commits.50-percentile":  true,
-		"chain/storage/commits.50-percentile":  true,
-		"chain/inserts.50-percentile":          true,
-	}
-}
-
-func (g *metricsCollector) GetMetricsEndpoint() string {
-	return fmt.Sprintf("http://127.0.0.1:%d/debug/metrics", g.metricsPort)
-}
-
-func (g *metricsCollector) GetMetrics() []metrics.BlockMetrics {
-	return g.metrics
-}
-
-func (g *metricsCollector) Collect(ctx context.Context, metrics *metrics.BlockMetrics) error {
-	resp, err := http.Get(g.GetMetricsEndpoint())
-	if err != nil {
-		return fmt.Errorf("failed to get metrics: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	var metricsData map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&metricsData); err != nil {
-		return fmt.Errorf("failed to decode metrics: %w", err)
-	}
-
-	metricTypes := g.GetMetricTypes()
-	for name, value := range metricsData {
-		if !metricTypes[name] {
-			continue
-		}
-		if v, ok := value.(float64); ok {
-			metrics.AddExecutionMetric(name, v)
-		}
-	}
+// defaultMetrics are the metrics scraped from geth unless overridden by a
+// metrics profile.
+var defaultMetrics = metrics.NewMetricSelection(
+	"chain/account/reads.50-percentile",
+	"chain/execution.50-percentile",
+	"chain/crossvalidation.50-percentile",
+	"chain/storage/reads.50-percentile",
+	"chain/account/updates.50-percentile",
+	"chain/account/hashes.50-percentile",
+	"chain/storage/updates.50-percentile",
+	"chain/validation.50-percentile",
+	"chain/write.50-percentile",
+	"chain/snapshot/commits.50-percentile",
+	"chain/triedb/commits.50-percentile",
+	"chain/account/commits.50-percentile",
+	"chain/storage/commits.50-percentile",
+	"chain/inserts.50-percentile",
+)
 
-	g.metrics = append(g.metrics, *metrics.Copy())
-	return nil
+func newMetricsScraper(options *config.InternalClientOptions, metricsPort int) metrics.Scraper {
+	url := fmt.Sprintf("http://127.0.0.1:%d/debug/metrics", metricsPort)
+	return metrics.NewJSONScraper(url, options.MetricsProfile.Selection("geth", defaultMetrics))
 }
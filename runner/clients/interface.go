@@ -1,9 +1,12 @@
 package clients
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/base/base-bench/runner/benchmark/portmanager"
+	"github.com/base/base-bench/runner/clients/generic"
 	"github.com/base/base-bench/runner/clients/geth"
 	"github.com/base/base-bench/runner/clients/rbuilder"
 	"github.com/base/base-bench/runner/clients/reth"
@@ -11,25 +14,27 @@ import (
 	"github.com/base/base-bench/runner/config"
 )
 
-// NewClient creates a new client based on the given client type.
-func NewClient(client Client, logger log.Logger, options *config.InternalClientOptions, portManager portmanager.PortManager) types.ExecutionClient {
-	switch client {
+// NewClient creates a new client for the given node type. Node types other
+// than the built-in clients are looked up in the loaded client descriptors.
+func NewClient(nodeType string, logger log.Logger, options *config.InternalClientOptions, portManager portmanager.PortManager) (types.ExecutionClient, error) {
+	switch nodeType {
 	case Reth:
-		return reth.NewRethClient(logger, options, portManager)
+		return reth.NewRethClient(logger, options, portManager), nil
 	case Geth:
-		return geth.NewGethClient(logger, options, portManager)
+		return geth.NewGethClient(logger, options, portManager), nil
 	case Rbuilder:
-		return rbuilder.NewRbuilderClient(logger, options, portManager)
-	default:
-		panic("unknown client")
+		return rbuilder.NewRbuilderClient(logger, options, portManager), nil
 	}
-}
 
-// Client is an enum for the different clients that can be used to run the chain.
-type Client uint
+	if descriptor, ok := options.ClientDescriptors[nodeType]; ok {
+		return generic.NewGenericClient(logger, options, portManager, descriptor), nil
+	}
+	return nil, fmt.Errorf("unsupported node type: %s", nodeType)
+}
 
+// Node types of the built-in clients.
 const (
-	Reth Client = iota
-	Geth
-	Rbuilder
+	Reth     = "reth"
+	Geth     = "geth"
+	Rbuilder = "rbuilder"
 )
@@ -2,7 +2,6 @@ package rbuilder
 
 import (
 	"context"
-	"errors"
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum/go-ethereum/log"
@@ -31,7 +30,7 @@ type RbuilderClient struct {
 
 	elClient types.ExecutionClient
 
-	metricsCollector metrics.Collector
+	metricsScraper metrics.Scraper
 }
 
 // NewRbuilderClient creates a new client for reth.
@@ -55,15 +54,12 @@ func (r *RbuilderClient) Run(ctx context.Context, cfg *types.RuntimeConfig) erro
 		return err
 	}
 
-	r.metricsCollector = newMetricsCollector(r.logger, r.elClient.Client(), int(r.elClient.MetricsPort()))
-	if r.metricsCollector == nil {
-		return errors.New("failed to create metrics collector")
-	}
+	r.metricsScraper = newMetricsScraper(r.options, r.elClient.MetricsPort())
 	return nil
 }
 
-func (r *RbuilderClient) MetricsCollector() metrics.Collector {
-	return r.metricsCollector
+func (r *RbuilderClient) MetricsScraper() metrics.Scraper {
+	return r.metricsScraper
 }
 
 // Stop stops the reth client.
@@ -89,3 +85,13 @@ func (r *RbuilderClient) AuthClient() client.RPC {
 func (r *RbuilderClient) MetricsPort() int {
 	return r.elClient.MetricsPort()
 }
+
+// Version returns the version of the rbuilder binary.
+func (r *RbuilderClient) Version(ctx context.Context) *types.ClientVersion {
+	return r.elClient.Version(ctx)
+}
+
+// PID returns the process ID of the running client.
+func (r *RbuilderClient) PID() int {
+	return r.elClient.PID()
+}
@@ -43,8 +43,8 @@ type RethClient struct {
 	stdout io.WriteCloser
 	stderr io.WriteCloser
 
-	binPath          string
-	metricsCollector metrics.Collector
+	binPath        string
+	metricsScraper metrics.Scraper
 }
 
 // NewRethClient creates a new client for reth.
@@ -66,8 +66,8 @@ func NewRethClientWithBin(logger log.Logger, options *config.InternalClientOptio
 	}
 }
 
-func (r *RethClient) MetricsCollector() metrics.Collector {
-	return r.metricsCollector
+func (r *RethClient) MetricsScraper() metrics.Scraper {
+	return r.metricsScraper
 }
 
 // Run runs the reth client with the given runtime config.
@@ -85,7 +85,11 @@ func (r *RethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 	// todo: make this dynamic eventually
 	args = append(args, "--http")
 	args = append(args, "--http.port", fmt.Sprintf("%d", r.rpcPort))
-	args = append(args, "--http.api", "eth,net,web3,miner")
+	httpAPI := "eth,net,web3,miner"
+	if r.options.DebugAPI {
+		httpAPI += ",debug"
+	}
+	args = append(args, "--http.api", httpAPI)
 	args = append(args, "--authrpc.port", fmt.Sprintf("%d", r.authRPCPort))
 	args = append(args, "--authrpc.jwtsecret", r.options.JWTSecretPath)
 	args = append(args, "--metrics", fmt.Sprintf("%d", r.metricsPort))
@@ -143,7 +147,8 @@ func (r *RethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 
 	r.logger.Debug("starting reth", "args", strings.Join(args, " "))
 
-	r.process = exec.Command(r.binPath, args...)
+	r.process = common.NewCommand(r.binPath, args, r.options.CPUSet)
+	r.process.Env = cfg.Env
 	r.process.Stdout = r.stdout
 	r.process.Stderr = r.stderr
 	err = r.process.Start()
@@ -160,7 +165,7 @@ func (r *RethClient) Run(ctx context.Context, cfg *types.RuntimeConfig) error {
 	}
 
 	r.client = ethclient.NewClient(rpcClient)
-	r.metricsCollector = newMetricsCollector(r.logger, r.client, int(r.metricsPort))
+	r.metricsScraper = newMetricsScraper(r.options, int(r.metricsPort))
 
 	err = common.WaitForRPC(ctx, r.client)
 	if err != nil {
@@ -225,3 +230,16 @@ func (r *RethClient) AuthClient() client.RPC {
 func (r *RethClient) MetricsPort() int {
 	return int(r.metricsPort)
 }
+
+// Version returns the version of the reth binary.
+func (r *RethClient) Version(ctx context.Context) *types.ClientVersion {
+	return common.GetClientVersion(ctx, r.logger, r.binPath, r.client)
+}
+
+// PID returns the process ID of the running client.
+func (r *RethClient) PID() int {
+	if r.process == nil || r.process.Process == nil {
+		return 0
+	}
+	return r.process.Process.Pid
+}
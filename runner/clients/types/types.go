@@ -13,6 +13,24 @@ type RuntimeConfig struct {
 	Stdout io.WriteCloser
 	Stderr io.WriteCloser
 	Args   []string
+	// Env is the environment of the client process in "KEY=value" form. If
+	// nil, the process inherits the runner's environment.
+	Env []string
+}
+
+// ClientVersion identifies the build of the client binary that produced a
+// run's results.
+type ClientVersion struct {
+	// NodeType is the node type the client was run as, e.g. "reth".
+	NodeType string `json:"nodeType"`
+	// Version is the output of running the binary with --version.
+	Version string `json:"version,omitempty"`
+	// ClientVersion is the result of web3_clientVersion.
+	ClientVersion string `json:"clientVersion,omitempty"`
+	// BinaryPath is the absolute path of the binary.
+	BinaryPath string `json:"binaryPath,omitempty"`
+	// BinarySHA256 is the hex encoded sha256 of the binary.
+	BinarySHA256 string `json:"binarySha256,omitempty"`
 }
 
 // ExecutionClient is an abstraction over the different clients that can be used to run the chain like
@@ -24,5 +42,12 @@ type ExecutionClient interface {
 	ClientURL() string // needed for external transaction payload workers
 	AuthClient() client.RPC
 	MetricsPort() int
-	MetricsCollector() metrics.Collector
+	// PID returns the process ID of the running client, or 0 if it is not
+	// running.
+	PID() int
+	// MetricsScraper scrapes the client's selected metrics.
+	MetricsScraper() metrics.Scraper
+	// Version returns the version of the running client. Parts that cannot
+	// be determined are left empty.
+	Version(ctx context.Context) *ClientVersion
 }
@@ -0,0 +1,136 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formatValue renders a metric value in a human readable unit. Latencies are
+// stored in nanoseconds and gas rates in gas per second.
+func formatValue(metric string, value float64) string {
+	switch {
+	case strings.HasPrefix(metric, "latency/"):
+		return fmt.Sprintf("%.2fms", value/1e6)
+	case strings.HasSuffix(metric, "/per_second"):
+		return fmt.Sprintf("%.2fMgas/s", value/1e6)
+	default:
+		return fmt.Sprintf("%.2f", value)
+	}
+}
+
+func formatDelta(delta float64) string {
+	return fmt.Sprintf("%+.1f%%", delta*100)
+}
+
+func verdict(m MetricComparison) string {
+	switch {
+	case m.Regression:
+		return "significant regression"
+	case m.Significant:
+		return "significant"
+	default:
+		return "not significant"
+	}
+}
+
+// WriteText writes a plain text report.
+func WriteText(w io.Writer, report *Report) error {
+	for _, c := range report.Comparisons {
+		if _, err := fmt.Fprintf(w, "%s\n  baseline: %s\n  candidate: %s\n", c.Label, strings.Join(c.BaselineOutputDirs, ", "), strings.Join(c.CandidateOutputDirs, ", ")); err != nil {
+			return err
+		}
+		if len(c.HostMismatch) > 0 {
+			if _, err := fmt.Fprintf(w, "  warning: runs are from different hosts (%s)\n", strings.Join(c.HostMismatch, ", ")); err != nil {
+				return err
+			}
+		}
+		for _, m := range c.Metrics {
+			_, err := fmt.Fprintf(w, "  %s %s p50 %s -> %s (%s), p99 %s -> %s (%s), mean %s, p=%.4f (%s)\n",
+				m.Role, m.Metric,
+				formatValue(m.Metric, m.Baseline.P50), formatValue(m.Metric, m.Candidate.P50), formatDelta(m.P50Delta),
+				formatValue(m.Metric, m.Baseline.P99), formatValue(m.Metric, m.Candidate.P99), formatDelta(m.P99Delta),
+				formatDelta(m.MeanDelta), m.PValue, verdict(m))
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	for _, label := range report.UnmatchedBaseline {
+		if _, err := fmt.Fprintf(w, "only in baseline: %s\n", label); err != nil {
+			return err
+		}
+	}
+	for _, label := range report.UnmatchedCandidate {
+		if _, err := fmt.Fprintf(w, "only in candidate: %s\n", label); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d matched runs, %d significant regressions (alpha=%g)\n", len(report.Comparisons), report.Regressions(), report.Alpha)
+	return err
+}
+
+// WriteMarkdown writes a Markdown report suitable for posting on a PR.
+func WriteMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Benchmark comparison\n\n")
+	fmt.Fprintf(&b, "%d matched runs, **%d significant regressions** (alpha=%g)\n\n", len(report.Comparisons), report.Regressions(), report.Alpha)
+
+	for _, c := range report.Comparisons {
+		fmt.Fprintf(&b, "### %s\n\n", c.Label)
+		if len(c.HostMismatch) > 0 {
+			fmt.Fprintf(&b, "> ⚠️ Runs are from different hosts (%s)\n\n", strings.Join(c.HostMismatch, ", "))
+		}
+		fmt.Fprintf(&b, "| Role | Metric | p50 | Δ p50 | p99 | Δ p99 | Δ mean | p-value | |\n")
+		fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|---|\n")
+		for _, m := range c.Metrics {
+			fmt.Fprintf(&b, "| %s | %s | %s → %s | %s | %s → %s | %s | %s | %.4f | %s |\n",
+				m.Role, m.Metric,
+				formatValue(m.Metric, m.Baseline.P50), formatValue(m.Metric, m.Candidate.P50), formatDelta(m.P50Delta),
+				formatValue(m.Metric, m.Baseline.P99), formatValue(m.Metric, m.Candidate.P99), formatDelta(m.P99Delta),
+				formatDelta(m.MeanDelta), m.PValue, verdict(m))
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	if len(report.UnmatchedBaseline) > 0 || len(report.UnmatchedCandidate) > 0 {
+		fmt.Fprintf(&b, "### Unmatched runs\n\n")
+		for _, label := range report.UnmatchedBaseline {
+			fmt.Fprintf(&b, "- only in baseline: `%s`\n", label)
+		}
+		for _, label := range report.UnmatchedCandidate {
+			fmt.Fprintf(&b, "- only in candidate: `%s`\n", label)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// Write writes the report in the given format.
+func Write(w io.Writer, report *Report, format string) error {
+	switch format {
+	case "json":
+		return WriteJSON(w, report)
+	case "markdown":
+		return WriteMarkdown(w, report)
+	case "text":
+		return WriteText(w, report)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,360 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/base/base-bench/benchmark/config"
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/pkg/errors"
+)
+
+type comparedMetric struct {
+	role           string
+	name           string
+	higherIsBetter bool
+}
+
+// comparedMetrics are the key metrics compared between runs.
+var comparedMetrics = []comparedMetric{
+	{role: benchmark.SequencerRole, name: types.UpdateForkChoiceLatencyMetric},
+	{role: benchmark.SequencerRole, name: types.SendTxsLatencyMetric},
+	{role: benchmark.SequencerRole, name: types.GetPayloadLatencyMetric},
+	{role: benchmark.SequencerRole, name: types.GasPerSecondMetric, higherIsBetter: true},
+	{role: benchmark.ValidatorRole, name: types.NewPayloadLatencyMetric},
+	{role: benchmark.ValidatorRole, name: types.GasPerSecondMetric, higherIsBetter: true},
+}
+
+// runSet is a set of runs along with the directory their output dirs are
+// relative to.
+type runSet struct {
+	runs    []benchmark.Run
+	baseDir string
+}
+
+// Service compares benchmark runs between a baseline and a candidate.
+type Service struct {
+	config *config.CompareCmdConfig
+	log    log.Logger
+}
+
+// NewService creates a new compare service
+func NewService(cfg *config.CompareCmdConfig, log log.Logger) *Service {
+	return &Service{
+		config: cfg,
+		log:    log,
+	}
+}
+
+func readMetadata(metadataPath string) (*benchmark.RunGroup, error) {
+	file, err := os.Open(metadataPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open metadata file")
+	}
+	defer func() { _ = file.Close() }()
+
+	var metadata benchmark.RunGroup
+	if err := json.NewDecoder(file).Decode(&metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata JSON")
+	}
+	return &metadata, nil
+}
+
+// loadRunSet resolves a source to a set of runs. The source is either a path
+// to a metadata.json file or an output directory, optionally followed by
+// #<BenchmarkRun ID>, or a BenchmarkRun ID (or test ID) within the configured
+// output directory. Since metadata.json keeps the runs of every earlier
+// BenchmarkRun, a path without an ID selects the latest BenchmarkRun in it.
+func (s *Service) loadRunSet(source string) (*runSet, error) {
+	sourcePath, benchmarkRunID := source, ""
+	if _, err := os.Stat(source); err != nil {
+		if i := strings.LastIndex(source, "#"); i >= 0 {
+			sourcePath, benchmarkRunID = source[:i], source[i+1:]
+		}
+	}
+
+	if info, err := os.Stat(sourcePath); err == nil {
+		metadataPath := sourcePath
+		if info.IsDir() {
+			metadataPath = filepath.Join(sourcePath, "metadata.json")
+		}
+		metadata, err := readMetadata(metadataPath)
+		if err != nil {
+			return nil, err
+		}
+		runs, err := s.selectBenchmarkRun(metadata.Runs, benchmarkRunID)
+		if err != nil {
+			return nil, errors.Wrap(err, metadataPath)
+		}
+		return &runSet{runs: runs, baseDir: filepath.Dir(metadataPath)}, nil
+	}
+
+	if s.config.OutputDir() == "" {
+		return nil, fmt.Errorf("%s is not a file and --output-dir is not set", source)
+	}
+
+	metadata, err := readMetadata(filepath.Join(s.config.OutputDir(), "metadata.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]benchmark.Run, 0)
+	for _, run := range metadata.Runs {
+		if run.ID == source || run.TestConfig[benchmark.BenchmarkRunTag] == source {
+			runs = append(runs, run)
+		}
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no runs found for %s", source)
+	}
+
+	return &runSet{runs: runs, baseDir: s.config.OutputDir()}, nil
+}
+
+// selectBenchmarkRun returns the runs of the given BenchmarkRun, or of the
+// BenchmarkRun of the most recently created run if the ID is empty.
+func (s *Service) selectBenchmarkRun(runs []benchmark.Run, benchmarkRunID string) ([]benchmark.Run, error) {
+	if len(runs) == 0 {
+		return nil, errors.New("no runs found")
+	}
+
+	var selected interface{} = benchmarkRunID
+	if benchmarkRunID == "" {
+		latest := runs[0]
+		benchmarkRuns := make(map[interface{}]bool)
+		for _, run := range runs {
+			benchmarkRuns[run.TestConfig[benchmark.BenchmarkRunTag]] = true
+			if run.CreatedAt != nil && (latest.CreatedAt == nil || run.CreatedAt.After(*latest.CreatedAt)) {
+				latest = run
+			}
+		}
+		selected = latest.TestConfig[benchmark.BenchmarkRunTag]
+		if len(benchmarkRuns) > 1 {
+			s.log.Info("Using the latest benchmark run, append #<id> to the path to select another", "benchmarkRun", selected, "benchmarkRuns", len(benchmarkRuns))
+		}
+	}
+
+	selectedRuns := make([]benchmark.Run, 0)
+	for _, run := range runs {
+		if run.TestConfig[benchmark.BenchmarkRunTag] == selected {
+			selectedRuns = append(selectedRuns, run)
+		}
+	}
+	if len(selectedRuns) == 0 {
+		return nil, fmt.Errorf("no runs found for benchmark run %s", benchmarkRunID)
+	}
+	return selectedRuns, nil
+}
+
+// matchConfig returns the test config used to match runs between sets.
+func (s *Service) matchConfig(run benchmark.Run) map[string]interface{} {
+	ignored := map[string]bool{benchmark.BenchmarkRunTag: true}
+	for _, tag := range s.config.IgnoreTags() {
+		ignored[tag] = true
+	}
+
+	cfg := make(map[string]interface{})
+	for k, v := range run.TestConfig {
+		if !ignored[k] {
+			cfg[k] = v
+		}
+	}
+	return cfg
+}
+
+func configLabel(cfg map[string]interface{}) string {
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		value := fmt.Sprintf("%v", cfg[k])
+		// numbers decoded from JSON are floats, avoid exponent notation
+		if f, ok := cfg[k].(float64); ok {
+			value = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", k, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indexRuns groups the successful runs by their match label. Runs sharing a
+// label, such as repetitions of the same matrix cell, are compared together
+// by pooling their block samples.
+func (s *Service) indexRuns(set *runSet) (map[string][]benchmark.Run, []string) {
+	index := make(map[string][]benchmark.Run)
+	labels := make([]string, 0)
+	for _, run := range set.runs {
+		if run.Result == nil || !run.Result.Success {
+			continue
+		}
+		label := configLabel(s.matchConfig(run))
+		if _, ok := index[label]; !ok {
+			labels = append(labels, label)
+		}
+		index[label] = append(index[label], run)
+	}
+	return index, labels
+}
+
+func readBlockMetrics(baseDir string, run benchmark.Run, role string) ([]metrics.BlockMetrics, error) {
+	metricsPath := filepath.Join(baseDir, run.OutputDir, fmt.Sprintf("metrics-%s.json", role))
+	data, err := os.ReadFile(metricsPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metrics file")
+	}
+
+	var blockMetrics []metrics.BlockMetrics
+	if err := json.Unmarshal(data, &blockMetrics); err != nil {
+		return nil, errors.Wrap(err, "failed to decode metrics file")
+	}
+	return blockMetrics, nil
+}
+
+// readPooledBlockMetrics reads the block metrics of every run in a group.
+func readPooledBlockMetrics(baseDir string, runs []benchmark.Run, role string) ([]metrics.BlockMetrics, error) {
+	pooled := make([]metrics.BlockMetrics, 0)
+	for _, run := range runs {
+		blockMetrics, err := readBlockMetrics(baseDir, run, role)
+		if err != nil {
+			return nil, errors.Wrapf(err, "run %s", run.OutputDir)
+		}
+		pooled = append(pooled, blockMetrics...)
+	}
+	return pooled, nil
+}
+
+func outputDirs(runs []benchmark.Run) []string {
+	dirs := make([]string, len(runs))
+	for i, run := range runs {
+		dirs[i] = run.OutputDir
+	}
+	return dirs
+}
+
+func relativeDelta(baseline float64, candidate float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+	return (candidate - baseline) / baseline
+}
+
+// CompareMetric compares two sets of block samples for a single metric.
+func CompareMetric(role string, metric string, higherIsBetter bool, baseline []float64, candidate []float64, alpha float64) MetricComparison {
+	baselineDist := metrics.NewDistribution(baseline)
+	candidateDist := metrics.NewDistribution(candidate)
+	pValue := metrics.MannWhitneyU(baseline, candidate)
+	significant := pValue < alpha
+
+	meanDelta := relativeDelta(baselineDist.Mean, candidateDist.Mean)
+	worse := meanDelta > 0
+	if higherIsBetter {
+		worse = meanDelta < 0
+	}
+
+	return MetricComparison{
+		Role:        role,
+		Metric:      metric,
+		Baseline:    baselineDist,
+		Candidate:   candidateDist,
+		MeanDelta:   meanDelta,
+		P50Delta:    relativeDelta(baselineDist.P50, candidateDist.P50),
+		P99Delta:    relativeDelta(baselineDist.P99, candidateDist.P99),
+		PValue:      pValue,
+		Significant: significant,
+		Regression:  significant && worse,
+	}
+}
+
+func (s *Service) compareRuns(baselineSet *runSet, baseline []benchmark.Run, candidateSet *runSet, candidate []benchmark.Run) RunComparison {
+	cfg := s.matchConfig(baseline[0])
+	comparison := RunComparison{
+		Label:               configLabel(cfg),
+		TestConfig:          cfg,
+		BaselineOutputDirs:  outputDirs(baseline),
+		CandidateOutputDirs: outputDirs(candidate),
+		HostMismatch:        baseline[0].Host.Diff(candidate[0].Host),
+		Metrics:             make([]MetricComparison, 0, len(comparedMetrics)),
+	}
+	if len(comparison.HostMismatch) > 0 {
+		s.log.Warn("Comparing runs from different hosts", "run", comparison.Label, "differences", comparison.HostMismatch)
+	}
+
+	for _, role := range []string{benchmark.SequencerRole, benchmark.ValidatorRole} {
+		baselineMetrics, err := readPooledBlockMetrics(baselineSet.baseDir, baseline, role)
+		if err != nil {
+			s.log.Warn("Skipping baseline metrics", "run", comparison.Label, "role", role, "err", err)
+			continue
+		}
+		candidateMetrics, err := readPooledBlockMetrics(candidateSet.baseDir, candidate, role)
+		if err != nil {
+			s.log.Warn("Skipping candidate metrics", "run", comparison.Label, "role", role, "err", err)
+			continue
+		}
+		for _, m := range comparedMetrics {
+			if m.role != role {
+				continue
+			}
+			baselineValues := metrics.GetMetricValues(baselineMetrics, m.name)
+			candidateValues := metrics.GetMetricValues(candidateMetrics, m.name)
+			if len(baselineValues) == 0 || len(candidateValues) == 0 {
+				continue
+			}
+			comparison.Metrics = append(comparison.Metrics, CompareMetric(role, m.name, m.higherIsBetter, baselineValues, candidateValues, s.config.Alpha()))
+		}
+	}
+
+	return comparison
+}
+
+// Compare loads the baseline and candidate runs, matches them by test config
+// and compares their per-block metrics.
+func (s *Service) Compare() (*Report, error) {
+	baselineSet, err := s.loadRunSet(s.config.Baseline())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load baseline runs")
+	}
+	candidateSet, err := s.loadRunSet(s.config.Candidate())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load candidate runs")
+	}
+
+	baselineRuns, baselineLabels := s.indexRuns(baselineSet)
+	candidateRuns, candidateLabels := s.indexRuns(candidateSet)
+
+	report := &Report{
+		Alpha:              s.config.Alpha(),
+		Comparisons:        make([]RunComparison, 0),
+		UnmatchedBaseline:  make([]string, 0),
+		UnmatchedCandidate: make([]string, 0),
+	}
+
+	for _, label := range baselineLabels {
+		candidate, ok := candidateRuns[label]
+		if !ok {
+			report.UnmatchedBaseline = append(report.UnmatchedBaseline, label)
+			continue
+		}
+		report.Comparisons = append(report.Comparisons, s.compareRuns(baselineSet, baselineRuns[label], candidateSet, candidate))
+	}
+
+	for _, label := range candidateLabels {
+		if _, ok := baselineRuns[label]; !ok {
+			report.UnmatchedCandidate = append(report.UnmatchedCandidate, label)
+		}
+	}
+
+	s.log.Info("Compared runs", "matched", len(report.Comparisons), "unmatchedBaseline", len(report.UnmatchedBaseline), "unmatchedCandidate", len(report.UnmatchedCandidate))
+	return report, nil
+}
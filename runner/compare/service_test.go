@@ -0,0 +1,160 @@
+package compare
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/base/base-bench/benchmark/config"
+	"github.com/base/base-bench/benchmark/flags"
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestConfigLabel(t *testing.T) {
+	label := configLabel(map[string]interface{}{
+		"NodeType":           "reth",
+		"GasLimit":           float64(30000000),
+		"TransactionPayload": "transfer-only",
+	})
+	require.Equal(t, "GasLimit=30000000 NodeType=reth TransactionPayload=transfer-only", label)
+}
+
+func TestCompareMetric(t *testing.T) {
+	baseline := []float64{100, 101, 99, 100, 102, 98, 100, 101, 99, 100}
+	slower := []float64{120, 121, 119, 120, 122, 118, 120, 121, 119, 120}
+
+	latency := CompareMetric("validator", "latency/new_payload", false, baseline, slower, 0.05)
+	require.True(t, latency.Significant)
+	require.True(t, latency.Regression)
+	require.InDelta(t, 0.2, latency.MeanDelta, 1e-9)
+
+	// the same change is an improvement when higher is better
+	throughput := CompareMetric("validator", "gas/per_second", true, baseline, slower, 0.05)
+	require.True(t, throughput.Significant)
+	require.False(t, throughput.Regression)
+
+	same := CompareMetric("validator", "latency/new_payload", false, baseline, baseline, 0.05)
+	require.False(t, same.Significant)
+	require.False(t, same.Regression)
+}
+
+// writeRuns writes the metrics of one repetition per entry in latencies, each
+// with validator new_payload latencies for its blocks, and returns the runs.
+func writeRuns(t *testing.T, dir string, benchmarkRunID string, createdAt time.Time, latencies [][]float64) []benchmark.Run {
+	runs := make([]benchmark.Run, 0, len(latencies))
+	for rep, values := range latencies {
+		outputDir := fmt.Sprintf("%s-%d", benchmarkRunID, rep)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, outputDir), 0755))
+
+		blockMetrics := make([]metrics.BlockMetrics, len(values))
+		for i, value := range values {
+			blockMetrics[i] = metrics.BlockMetrics{
+				BlockNumber:      uint64(i + 1),
+				ExecutionMetrics: map[string]interface{}{types.NewPayloadLatencyMetric: value},
+			}
+		}
+		data, err := json.Marshal(blockMetrics)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, outputDir, "metrics-validator.json"), data, 0644))
+
+		runs = append(runs, benchmark.Run{
+			ID:         benchmarkRunID,
+			OutputDir:  outputDir,
+			TestConfig: map[string]interface{}{"NodeType": "geth", benchmark.BenchmarkRunTag: benchmarkRunID},
+			Result:     &benchmark.RunResult{Success: true, Complete: true},
+			CreatedAt:  &createdAt,
+			Group:      benchmarkRunID,
+			Repetition: rep,
+		})
+	}
+	return runs
+}
+
+func writeMetadata(t *testing.T, dir string, runs []benchmark.Run) {
+	data, err := json.Marshal(benchmark.RunGroup{Runs: runs})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.json"), data, 0644))
+}
+
+// writeRunSet writes a metadata.json with a single BenchmarkRun.
+func writeRunSet(t *testing.T, latencies [][]float64) string {
+	dir := t.TempDir()
+	writeMetadata(t, dir, writeRuns(t, dir, "cell-0", time.Now(), latencies))
+	return dir
+}
+
+func newTestService(t *testing.T, baseline string, candidate string) *Service {
+	set := flag.NewFlagSet("compare", flag.ContinueOnError)
+	for _, f := range flags.CompareFlags {
+		require.NoError(t, f.Apply(set))
+	}
+	require.NoError(t, set.Parse([]string{baseline, candidate}))
+	cliCtx := cli.NewContext(cli.NewApp(), set, nil)
+	return NewService(config.NewCompareCmdConfig(cliCtx), log.New())
+}
+
+func TestComparePoolsRepetitions(t *testing.T) {
+	baseline := writeRunSet(t, [][]float64{
+		{100, 101, 99, 100, 102},
+		{98, 100, 101, 99, 100},
+	})
+	candidate := writeRunSet(t, [][]float64{
+		{120, 121, 119},
+		{120, 122, 118},
+		{120, 121, 119},
+	})
+
+	report, err := newTestService(t, baseline, candidate).Compare()
+	require.NoError(t, err)
+	require.Len(t, report.Comparisons, 1)
+	require.Empty(t, report.UnmatchedBaseline)
+	require.Empty(t, report.UnmatchedCandidate)
+
+	comparison := report.Comparisons[0]
+	require.Equal(t, []string{"cell-0-0", "cell-0-1"}, comparison.BaselineOutputDirs)
+	require.Equal(t, []string{"cell-0-0", "cell-0-1", "cell-0-2"}, comparison.CandidateOutputDirs)
+
+	require.Len(t, comparison.Metrics, 1)
+	newPayload := comparison.Metrics[0]
+	require.Equal(t, types.NewPayloadLatencyMetric, newPayload.Metric)
+	require.Equal(t, 10, newPayload.Baseline.Count)
+	require.Equal(t, 9, newPayload.Candidate.Count)
+	require.InDelta(t, 0.2, newPayload.MeanDelta, 1e-9)
+	require.True(t, newPayload.Regression)
+}
+
+func TestCompareSelectsBenchmarkRun(t *testing.T) {
+	// metadata.json keeps the runs of earlier BenchmarkRuns of the same config
+	baseline := t.TempDir()
+	now := time.Now()
+	older := writeRuns(t, baseline, "older", now.Add(-time.Hour), [][]float64{{200, 201, 199, 200, 202}})
+	latest := writeRuns(t, baseline, "latest", now, [][]float64{{100, 101, 99, 100, 102}})
+	writeMetadata(t, baseline, append(latest, older...))
+
+	candidate := writeRunSet(t, [][]float64{{100, 101, 99, 100, 102}})
+
+	report, err := newTestService(t, baseline, candidate).Compare()
+	require.NoError(t, err)
+	require.Len(t, report.Comparisons, 1)
+	require.Equal(t, []string{"latest-0"}, report.Comparisons[0].BaselineOutputDirs)
+	require.Equal(t, 5, report.Comparisons[0].Metrics[0].Baseline.Count)
+	require.False(t, report.Comparisons[0].Metrics[0].Significant)
+
+	report, err = newTestService(t, filepath.Join(baseline, "metadata.json")+"#older", candidate).Compare()
+	require.NoError(t, err)
+	require.Len(t, report.Comparisons, 1)
+	require.Equal(t, []string{"older-0"}, report.Comparisons[0].BaselineOutputDirs)
+	require.InDelta(t, 200.4, report.Comparisons[0].Metrics[0].Baseline.Mean, 1e-9)
+
+	_, err = newTestService(t, baseline+"#missing", candidate).Compare()
+	require.ErrorContains(t, err, "no runs found for benchmark run missing")
+}
@@ -0,0 +1,63 @@
+package compare
+
+import (
+	"github.com/base/base-bench/runner/metrics"
+)
+
+// MetricComparison compares the per-block samples of a single metric between
+// a baseline and a candidate run.
+type MetricComparison struct {
+	Role      string               `json:"role"`
+	Metric    string               `json:"metric"`
+	Baseline  metrics.Distribution `json:"baseline"`
+	Candidate metrics.Distribution `json:"candidate"`
+
+	// MeanDelta, P50Delta and P99Delta are relative changes from baseline to
+	// candidate, e.g. 0.12 for +12%.
+	MeanDelta float64 `json:"meanDelta"`
+	P50Delta  float64 `json:"p50Delta"`
+	P99Delta  float64 `json:"p99Delta"`
+
+	// PValue is the two-sided Mann-Whitney U p-value over the block samples.
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+	// Regression is set when the change is significant and in the worse
+	// direction for this metric.
+	Regression bool `json:"regression"`
+}
+
+// RunComparison holds the metric comparisons for the baseline and candidate
+// runs sharing a test config. The block samples of repeated runs are pooled.
+type RunComparison struct {
+	Label               string                 `json:"label"`
+	TestConfig          map[string]interface{} `json:"testConfig"`
+	BaselineOutputDirs  []string               `json:"baselineOutputDirs"`
+	CandidateOutputDirs []string               `json:"candidateOutputDirs"`
+	// HostMismatch lists the host fields that differ between the baseline
+	// and candidate runs, in which case the comparison may be unreliable.
+	HostMismatch []string           `json:"hostMismatch,omitempty"`
+	Metrics      []MetricComparison `json:"metrics"`
+}
+
+// Report is the result of comparing two sets of benchmark runs.
+type Report struct {
+	Alpha       float64         `json:"alpha"`
+	Comparisons []RunComparison `json:"comparisons"`
+	// UnmatchedBaseline and UnmatchedCandidate list the labels of runs that
+	// had no counterpart in the other set.
+	UnmatchedBaseline  []string `json:"unmatchedBaseline"`
+	UnmatchedCandidate []string `json:"unmatchedCandidate"`
+}
+
+// Regressions returns the number of significant regressions in the report.
+func (r *Report) Regressions() int {
+	count := 0
+	for _, c := range r.Comparisons {
+		for _, m := range c.Metrics {
+			if m.Regression {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -1,13 +1,18 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/urfave/cli/v2"
 
 	"github.com/base/base-bench/runner/benchmark/portmanager"
+	genericoptions "github.com/base/base-bench/runner/clients/generic/options"
 	gethoptions "github.com/base/base-bench/runner/clients/geth/options"
 	rbuilderoptions "github.com/base/base-bench/runner/clients/rbuilder/options"
 	rethoptions "github.com/base/base-bench/runner/clients/reth/options"
 	"github.com/base/base-bench/runner/flags"
+	"github.com/base/base-bench/runner/metrics"
 )
 
 // ClientOptions is the common options object that gets passed to execution clients.
@@ -16,6 +21,7 @@ type ClientOptions struct {
 	rethoptions.RethOptions
 	gethoptions.GethOptions
 	rbuilderoptions.RbuilderOptions
+	genericoptions.GenericOptions
 	PortOverrides PortOverrides
 }
 
@@ -29,6 +35,14 @@ type InternalClientOptions struct {
 	TestDirPath   string
 	JWTSecret     string
 	MetricsPath   string
+
+	// CPUSet is the list of CPUs the client process is pinned to, e.g. "0-3".
+	// Empty means no pinning.
+	CPUSet string
+
+	// DebugAPI exposes the debug RPC namespace, which the consistency check
+	// uses to fetch raw blocks and traces.
+	DebugAPI bool
 }
 
 type PortOverrides map[string]map[portmanager.PortPurpose]uint64
@@ -48,6 +62,9 @@ func ReadClientOptions(ctx *cli.Context) ClientOptions {
 	// TODO: allow overriding ports via flags
 
 	options := ClientOptions{
+		CommonOptions: CommonOptions{
+			MetricsProfilePath: ctx.String(flags.MetricsProfile),
+		},
 		PortOverrides: make(PortOverrides),
 		RethOptions: rethoptions.RethOptions{
 			RethBin: ctx.String(flags.RethBin),
@@ -58,10 +75,55 @@ func ReadClientOptions(ctx *cli.Context) ClientOptions {
 		RbuilderOptions: rbuilderoptions.RbuilderOptions{
 			RbuilderBin: ctx.String(flags.RbuilderBin),
 		},
+		GenericOptions: genericoptions.GenericOptions{
+			ClientDescriptorPaths: ctx.StringSlice(flags.ClientDescriptor),
+		},
 	}
 
 	return options
 }
 
+// builtinClients are the node types with client-specific implementations.
+var builtinClients = []string{"reth", "geth", "rbuilder"}
+
+// LoadClientDescriptors loads the client descriptor files. Descriptor names
+// must be unique and must not shadow a built-in client.
+func (o *ClientOptions) LoadClientDescriptors() error {
+	o.ClientDescriptors = make(map[string]*genericoptions.ClientDescriptor)
+	for _, path := range o.ClientDescriptorPaths {
+		descriptor, err := genericoptions.LoadClientDescriptor(path)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(builtinClients, descriptor.Name) {
+			return fmt.Errorf("client descriptor %s shadows the built-in %s client", path, descriptor.Name)
+		}
+		if _, ok := o.ClientDescriptors[descriptor.Name]; ok {
+			return fmt.Errorf("duplicate client descriptor name %q in %s", descriptor.Name, path)
+		}
+		o.ClientDescriptors[descriptor.Name] = descriptor
+	}
+	return nil
+}
+
+// LoadMetricsProfile loads the metrics profile file if one is set.
+func (o *ClientOptions) LoadMetricsProfile() error {
+	if o.MetricsProfilePath == "" {
+		return nil
+	}
+	profile, err := metrics.LoadMetricsProfile(o.MetricsProfilePath)
+	if err != nil {
+		return err
+	}
+	o.MetricsProfile = profile
+	return nil
+}
+
 // CommonOptions are common client configuration options.
-type CommonOptions struct{}
+type CommonOptions struct {
+	// MetricsProfile is loaded from MetricsProfilePath and selects the
+	// metrics scraped from each node type, overriding the clients' built-in
+	// selection.
+	MetricsProfilePath string
+	MetricsProfile     metrics.MetricsProfile
+}
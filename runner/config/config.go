@@ -3,10 +3,17 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	appFlags "github.com/base/base-bench/benchmark/flags"
+	"github.com/base/base-bench/runner/metrics"
+	"github.com/base/base-bench/runner/tracing"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/urfave/cli/v2"
 )
 
@@ -15,33 +22,65 @@ import (
 type Config interface {
 	Check() error
 	LogConfig() oplog.CLIConfig
+	MetricsConfig() opmetrics.CLIConfig
 	ClientOptions() ClientOptions
 	ConfigPath() string
 	DataDir() string
 	OutputDir() string
 	TxFuzzBinary() string
 	ProxyPort() int
+	ResumeBenchmarkRun() string
+	Parallelism() int
+	ResourceSampleInterval() time.Duration
+	MeasureDataDir() bool
+	MetricsScrapeInterval() time.Duration
+	MetricsOutputs() []string
+	MetricsRemoteWriteURL() string
+	TracingOptions() tracing.Options
 }
 
 type config struct {
 	logConfig     oplog.CLIConfig
+	metricsConfig opmetrics.CLIConfig
 	configPath    string
 	dataDir       string
 	outputDir     string
 	clientOptions ClientOptions
 	txFuzzBinary  string
 	proxyPort     int
+	resume        string
+	parallelism   int
+
+	resourceSampleInterval time.Duration
+	measureDataDir         bool
+	metricsScrapeInterval  time.Duration
+	metricsOutputs         []string
+	metricsRemoteWriteURL  string
+	tracingOptions         tracing.Options
 }
 
 func NewConfig(ctx *cli.Context) Config {
 	return &config{
 		logConfig:     oplog.ReadCLIConfig(ctx),
+		metricsConfig: opmetrics.ReadCLIConfig(ctx),
 		configPath:    ctx.String(appFlags.ConfigFlagName),
 		dataDir:       ctx.String(appFlags.RootDirFlagName),
 		outputDir:     ctx.String(appFlags.OutputDirFlagName),
 		txFuzzBinary:  ctx.String(appFlags.TxFuzzBinFlagName),
 		proxyPort:     ctx.Int(appFlags.ProxyPortFlagName),
-		clientOptions: ReadClientOptions(ctx),
+		resume:        ctx.String(appFlags.ResumeFlagName),
+		parallelism:   ctx.Int(appFlags.ParallelismFlagName),
+
+		resourceSampleInterval: ctx.Duration(appFlags.ResourceSampleIntervalFlagName),
+		measureDataDir:         ctx.Bool(appFlags.MeasureDataDirFlagName),
+		metricsScrapeInterval:  ctx.Duration(appFlags.MetricsScrapeIntervalFlagName),
+		metricsOutputs:         ctx.StringSlice(appFlags.MetricsOutputFlagName),
+		metricsRemoteWriteURL:  ctx.String(appFlags.MetricsRemoteWriteURLFlagName),
+		clientOptions:          ReadClientOptions(ctx),
+		tracingOptions: tracing.Options{
+			Endpoint: ctx.String(appFlags.TraceEndpointFlagName),
+			File:     ctx.String(appFlags.TraceFileFlagName),
+		},
 	}
 }
 
@@ -61,6 +100,52 @@ func (c *config) ProxyPort() int {
 	return c.proxyPort
 }
 
+// ResumeBenchmarkRun returns the BenchmarkRun ID to resume, or an empty string
+// to start a new benchmark run.
+func (c *config) ResumeBenchmarkRun() string {
+	return c.resume
+}
+
+// Parallelism returns the number of test runs to execute concurrently.
+func (c *config) Parallelism() int {
+	return c.parallelism
+}
+
+// ResourceSampleInterval returns the interval at which client processes are
+// sampled. Zero disables sampling.
+func (c *config) ResourceSampleInterval() time.Duration {
+	return c.resourceSampleInterval
+}
+
+// MeasureDataDir returns whether the size of each client's datadir is
+// measured after every block.
+func (c *config) MeasureDataDir() bool {
+	return c.measureDataDir
+}
+
+// MetricsScrapeInterval returns the interval at which client metrics are
+// scraped. Zero scrapes once at the end of every block.
+func (c *config) MetricsScrapeInterval() time.Duration {
+	return c.metricsScrapeInterval
+}
+
+// MetricsOutputs returns the formats per-block metrics are written in besides
+// metrics.json.
+func (c *config) MetricsOutputs() []string {
+	return c.metricsOutputs
+}
+
+// MetricsRemoteWriteURL returns the Prometheus remote-write endpoint metrics
+// are pushed to.
+func (c *config) MetricsRemoteWriteURL() string {
+	return c.metricsRemoteWriteURL
+}
+
+// TracingOptions returns where traces of the Engine API calls are exported.
+func (c *config) TracingOptions() tracing.Options {
+	return c.tracingOptions
+}
+
 func (c *config) Check() error {
 	if c.configPath == "" {
 		return errors.New("config path is required")
@@ -71,6 +156,16 @@ func (c *config) Check() error {
 		return fmt.Errorf("config file does not exist: %w", err)
 	}
 
+	if c.parallelism < 1 {
+		return errors.New("parallelism must be at least 1")
+	}
+
+	return c.checkClientConfig()
+}
+
+// checkClientConfig validates the options shared by every command that runs
+// clients.
+func (c *config) checkClientConfig() error {
 	if c.dataDir == "" {
 		return errors.New("data dir is required")
 	}
@@ -79,6 +174,43 @@ func (c *config) Check() error {
 		return errors.New("output dir is required")
 	}
 
+	if c.resourceSampleInterval < 0 {
+		return errors.New("resource sample interval must not be negative")
+	}
+
+	if c.metricsScrapeInterval < 0 {
+		return errors.New("metrics scrape interval must not be negative")
+	}
+
+	for _, output := range c.metricsOutputs {
+		if !slices.Contains(metrics.MetricsOutputs, output) {
+			return fmt.Errorf("unknown metrics output %q, expected one of %s", output, strings.Join(metrics.MetricsOutputs, ", "))
+		}
+	}
+
+	if slices.Contains(c.metricsOutputs, metrics.MetricsOutputRemoteWrite) && c.metricsRemoteWriteURL == "" {
+		return errors.New("metrics remote-write URL is required for remote-write output")
+	}
+
+	if endpoint := c.tracingOptions.Endpoint; endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid trace endpoint %q, expected an http or https URL", endpoint)
+		}
+	}
+
+	if err := c.metricsConfig.Check(); err != nil {
+		return fmt.Errorf("invalid metrics config: %w", err)
+	}
+
+	if err := c.clientOptions.LoadClientDescriptors(); err != nil {
+		return err
+	}
+
+	if err := c.clientOptions.LoadMetricsProfile(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -86,6 +218,12 @@ func (c *config) LogConfig() oplog.CLIConfig {
 	return c.logConfig
 }
 
+// MetricsConfig returns the config of the server exposing live benchmark
+// metrics.
+func (c *config) MetricsConfig() opmetrics.CLIConfig {
+	return c.metricsConfig
+}
+
 func (c *config) ClientOptions() ClientOptions {
 	return c.clientOptions
 }
@@ -93,3 +231,20 @@ func (c *config) ClientOptions() ClientOptions {
 func (c *config) TxFuzzBinary() string {
 	return c.txFuzzBinary
 }
+
+// replayConfig is the runtime config of the replay command, which takes the
+// chain and blocks from a recording instead of a benchmark config file.
+type replayConfig struct {
+	*config
+}
+
+// NewReplayConfig reads the runtime config of the replay command.
+func NewReplayConfig(ctx *cli.Context) Config {
+	return &replayConfig{
+		config: NewConfig(ctx).(*config),
+	}
+}
+
+func (c *replayConfig) Check() error {
+	return c.checkClientConfig()
+}
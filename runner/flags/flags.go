@@ -10,6 +10,9 @@ const (
 	RethBin     = "reth-bin"
 	RbuilderBin = "rbuilder-bin"
 	GethBin     = "geth-bin"
+
+	ClientDescriptor = "client-descriptor"
+	MetricsProfile   = "metrics-profile"
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -32,5 +35,15 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			Value:   "rbuilder",
 			EnvVars: opservice.PrefixEnvVar(envPrefix, "RBUILDER_BIN"),
 		},
+		&cli.StringSliceFlag{
+			Name:    ClientDescriptor,
+			Usage:   "Path of a client descriptor file; its name can be used as a node type. Can be repeated",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "CLIENT_DESCRIPTORS"),
+		},
+		&cli.StringFlag{
+			Name:    MetricsProfile,
+			Usage:   "Path of a metrics profile file selecting the metrics scraped from each client",
+			EnvVars: opservice.PrefixEnvVar(envPrefix, "METRICS_PROFILE"),
+		},
 	}
 }
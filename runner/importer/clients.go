@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/base/base-bench/runner/benchmark"
+)
+
+// ClientSummary counts the runs produced by one client build.
+type ClientSummary struct {
+	NodeType      string
+	Version       string
+	ClientVersion string
+	BinarySHA256  string
+	Runs          int
+}
+
+// String formats the client build on a single line.
+func (c ClientSummary) String() string {
+	parts := []string{c.NodeType}
+	if c.Version != "" {
+		parts = append(parts, c.Version)
+	}
+	if c.ClientVersion != "" {
+		parts = append(parts, fmt.Sprintf("(%s)", c.ClientVersion))
+	}
+	if c.BinarySHA256 != "" {
+		parts = append(parts, fmt.Sprintf("sha256:%.12s", c.BinarySHA256))
+	}
+	return fmt.Sprintf("%s: %d runs", strings.Join(parts, " "), c.Runs)
+}
+
+// SummarizeClients returns the distinct client builds recorded on the runs
+// along with the number of runs each was used in. Runs recorded before client
+// versions were captured are not counted.
+func SummarizeClients(runs []benchmark.Run) []ClientSummary {
+	counts := make(map[ClientSummary]int)
+	for _, run := range runs {
+		seen := make(map[ClientSummary]bool)
+		for _, version := range run.Clients {
+			if version == nil {
+				continue
+			}
+			key := ClientSummary{
+				NodeType:      version.NodeType,
+				Version:       firstLine(version.Version),
+				ClientVersion: version.ClientVersion,
+				BinarySHA256:  version.BinarySHA256,
+			}
+			// the sequencer and validator usually run the same binary
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+		}
+	}
+
+	summaries := make([]ClientSummary, 0, len(counts))
+	for summary, n := range counts {
+		summary.Runs = n
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].String() < summaries[j].String()
+	})
+	return summaries
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
@@ -53,6 +53,7 @@ func RunInteractive(summary *ImportSummary, destMetadata *benchmark.RunGroup) (*
 	if len(summary.Conflicts) > 0 {
 		fmt.Printf("  ⚠️  Conflicts detected: %d run IDs already exist\n", len(summary.Conflicts))
 	}
+	printClients(summary.Clients)
 	fmt.Printf("\n")
 
 	// First, ask about BenchmarkRun strategy
@@ -210,3 +211,14 @@ func RunInteractive(summary *ImportSummary, destMetadata *benchmark.RunGroup) (*
 
 	return srcTag, destTag, benchmarkRunOpt, true, nil
 }
+
+// printClients prints the client builds of the imported runs.
+func printClients(clients []ClientSummary) {
+	if len(clients) == 0 {
+		return
+	}
+	fmt.Printf("  • Clients:\n")
+	for _, c := range clients {
+		fmt.Printf("      %s\n", c)
+	}
+}
@@ -401,6 +401,7 @@ func (s *Service) MergeMetadata(srcMetadata, destMetadata *benchmark.RunGroup, s
 		SrcTagApplied:     srcTag,
 		DestTagApplied:    destTag,
 		Conflicts:         conflicts,
+		Clients:           SummarizeClients(srcRuns),
 	}
 
 	mergedMetadata := &benchmark.RunGroup{
@@ -31,6 +31,8 @@ type ImportSummary struct {
 	SrcTagApplied     *config.TagConfig
 	DestTagApplied    *config.TagConfig
 	Conflicts         []string
+	// Clients are the client builds that produced the imported runs.
+	Clients []ClientSummary
 }
 
 // BenchmarkRunOption represents how to handle BenchmarkRun for imported runs
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DataDirSizeMetric is the total size of the client's datadir in bytes. The
+// size of each subdirectory is recorded as "datadir/size/<path>".
+const DataDirSizeMetric = "datadir/size"
+
+// DataDirSizeDepth is how many levels of subdirectories are broken down, deep
+// enough to separate e.g. geth's geth/chaindata/ancient.
+const DataDirSizeDepth = 3
+
+// DataDirSize returns the total size in bytes of the files in a directory and
+// the size of each subdirectory up to DataDirSizeDepth levels deep, keyed by
+// its slash-separated path relative to dir.
+func DataDirSize(dir string) (int64, map[string]int64, error) {
+	var total int64
+	subdirs := make(map[string]int64)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// files may be removed by compaction while walking
+			if p != dir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			// directories are visited before their contents, so empty ones
+			// are still reported
+			if rel != "." && strings.Count(rel, "/") < DataDirSizeDepth {
+				subdirs[rel] = 0
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		size := info.Size()
+		total += size
+
+		// add the file to each of its parent directories within the depth
+		parts := strings.Split(rel, "/")
+		for i := 1; i < len(parts) && i <= DataDirSizeDepth; i++ {
+			subdirs[strings.Join(parts[:i], "/")] += size
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return total, subdirs, nil
+}
+
+type dataDirCollector struct {
+	Collector
+	log     log.Logger
+	dataDir string
+}
+
+// NewDataDirCollector wraps a collector so that the size of the client's
+// datadir is added to every block's metrics.
+func NewDataDirCollector(log log.Logger, collector Collector, dataDir string) Collector {
+	return &dataDirCollector{
+		Collector: collector,
+		log:       log,
+		dataDir:   dataDir,
+	}
+}
+
+func (d *dataDirCollector) Collect(ctx context.Context, m *BlockMetrics) error {
+	total, subdirs, err := DataDirSize(d.dataDir)
+	if err != nil {
+		d.log.Warn("Failed to measure datadir size", "dir", d.dataDir, "err", err)
+	} else {
+		m.AddExecutionMetric(DataDirSizeMetric, float64(total))
+		for subdir, size := range subdirs {
+			m.AddExecutionMetric(DataDirSizeMetric+"/"+subdir, float64(size))
+		}
+	}
+	return d.Collector.Collect(ctx, m)
+}
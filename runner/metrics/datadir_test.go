@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataDirSize(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name string, size int) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, make([]byte, size), 0644))
+	}
+	write("jwt.hex", 10)
+	write("db/mdbx.dat", 100)
+	write("static_files/headers/0", 20)
+	write("geth/chaindata/ancient/chain/bodies.cdat", 1000)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+
+	total, subdirs, err := DataDirSize(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(1130), total)
+	require.Equal(t, map[string]int64{
+		"db":                     100,
+		"static_files":           20,
+		"static_files/headers":   20,
+		"geth":                   1000,
+		"geth/chaindata":         1000,
+		"geth/chaindata/ancient": 1000,
+		"empty":                  0,
+	}, subdirs)
+}
+
+func TestDataDirSizeMissing(t *testing.T) {
+	_, _, err := DataDirSize(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -8,6 +8,8 @@ import (
 	"math"
 	"os"
 	"path"
+	"slices"
+	"strings"
 	"time"
 
 	io_prometheus_client "github.com/prometheus/client_model/go"
@@ -19,8 +21,12 @@ type Collector interface {
 }
 
 type BlockMetrics struct {
-	BlockNumber      uint64
-	Timestamp        time.Time
+	BlockNumber uint64
+	Timestamp   time.Time
+	// StartTime and EndTime bound the processing of the block, and are used
+	// to align scraped metrics with it.
+	StartTime        time.Time
+	EndTime          time.Time
 	prevMetrics      map[string]*io_prometheus_client.Metric
 	ExecutionMetrics map[string]interface{}
 }
@@ -45,17 +51,59 @@ func (m *BlockMetrics) Copy() *BlockMetrics {
 	maps.Copy(newPrevMetrics, m.prevMetrics)
 	return &BlockMetrics{
 		BlockNumber:      m.BlockNumber,
+		StartTime:        m.StartTime,
+		EndTime:          m.EndTime,
 		prevMetrics:      newPrevMetrics,
 		ExecutionMetrics: newMetrics,
 		Timestamp:        m.Timestamp,
 	}
 }
 
+// SeriesKey returns the key of a Prometheus series: its name, followed by its
+// labels sorted by name as name{label=value,...} if it has any.
+func SeriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	names := slices.Sorted(maps.Keys(labels))
+	pairs := make([]string, len(names))
+	for i, label := range names {
+		pairs[i] = label + "=" + labels[label]
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func seriesLabels(series *io_prometheus_client.Metric) map[string]string {
+	labels := make(map[string]string, len(series.GetLabel()))
+	for _, label := range series.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	return labels
+}
+
+// UpdatePrometheusMetric records the value of a Prometheus series under its
+// series key, so that each label set of a family is stored separately.
 func (m *BlockMetrics) UpdatePrometheusMetric(name string, value *io_prometheus_client.Metric) error {
+	key := SeriesKey(name, seriesLabels(value))
+	v, ok, err := m.PrometheusValue(key, value)
+	if err != nil {
+		return err
+	}
+	if ok {
+		m.ExecutionMetrics[key] = v
+	}
+	return nil
+}
+
+// PrometheusValue returns the value of a Prometheus series for this block.
+// Histograms and summaries are averaged over the observations since the
+// previous value of the series with the same key. It returns false if there
+// is no value, e.g. for NaN gauges or histograms without new observations.
+func (m *BlockMetrics) PrometheusValue(key string, value *io_prometheus_client.Metric) (float64, bool, error) {
 	if value.Histogram != nil {
 		// get the average change in sum divided by the average change in count
 		prevSum := 0.0
-		prevValue, ok := m.prevMetrics[name]
+		prevValue, ok := m.prevMetrics[key]
 		if !ok {
 			prevValue = nil
 		}
@@ -69,7 +117,7 @@ func (m *BlockMetrics) UpdatePrometheusMetric(name string, value *io_prometheus_
 			sum = *value.Histogram.SampleSum
 		}
 		prevCount := 0.0
-		m.prevMetrics[name] = value
+		m.prevMetrics[key] = value
 
 		if prevValue != nil {
 			if prevValue.Histogram.SampleCount != nil {
@@ -85,24 +133,24 @@ func (m *BlockMetrics) UpdatePrometheusMetric(name string, value *io_prometheus_
 			averageChange := (sum - prevSum) / deltaCount
 
 			if !math.IsNaN(averageChange) {
-				m.ExecutionMetrics[name] = averageChange
+				return averageChange, true, nil
 			}
 		}
 	} else if value.Gauge != nil {
 		if value.Gauge.Value != nil && !math.IsNaN(*value.Gauge.Value) {
-			m.ExecutionMetrics[name] = *value.Gauge.Value
+			return *value.Gauge.Value, true, nil
 		}
 		// NaN values and nil values are silently omitted
 	} else if value.Counter != nil {
 		if value.Counter.Value != nil && !math.IsNaN(*value.Counter.Value) {
-			m.ExecutionMetrics[name] = *value.Counter.Value
+			return *value.Counter.Value, true, nil
 		}
 		// NaN values and nil values are silently omitted
 	} else if value.Summary != nil {
 		// get the average change in sum divided by the average change in count
 		prevSum := 0.0
 
-		prevValue, ok := m.prevMetrics[name]
+		prevValue, ok := m.prevMetrics[key]
 		if !ok {
 			prevValue = nil
 		}
@@ -126,19 +174,20 @@ func (m *BlockMetrics) UpdatePrometheusMetric(name string, value *io_prometheus_
 			count = float64(*value.Summary.SampleCount)
 		}
 		denom := count - prevCount
-		m.prevMetrics[name] = value
+		m.prevMetrics[key] = value
 		if denom != 0 {
 			averageChange := (sum - prevSum) / denom
 			if !math.IsNaN(averageChange) {
-				m.ExecutionMetrics[name] = averageChange
+				return averageChange, true, nil
 			}
 		}
 
 	} else {
-		return fmt.Errorf("invalid metric type for %s: %#v", name, value)
+		return 0, false, fmt.Errorf("invalid metric type for %s: %#v", key, value)
 	}
-	return nil
+	return 0, false, nil
 }
+
 func (m *BlockMetrics) AddExecutionMetric(name string, value interface{}) {
 	m.ExecutionMetrics[name] = value
 }
@@ -243,6 +243,37 @@ func TestBlockMetrics_UpdatePrometheusMetric_NilValues(t *testing.T) {
 }
 
 // Helper functions
+func TestSeriesKey(t *testing.T) {
+	require.Equal(t, "reth_db_table_size", SeriesKey("reth_db_table_size", nil))
+	require.Equal(t, "reth_db_table_size{db=state,table=Headers}", SeriesKey("reth_db_table_size", map[string]string{"table": "Headers", "db": "state"}))
+}
+
+func TestBlockMetrics_UpdatePrometheusMetric_Labels(t *testing.T) {
+	m := NewBlockMetrics()
+
+	histogram := func(table string, sum float64, count uint64) *io_prometheus_client.Metric {
+		return &io_prometheus_client.Metric{
+			Label: []*io_prometheus_client.LabelPair{
+				{Name: stringPtr("table"), Value: stringPtr(table)},
+			},
+			Histogram: &io_prometheus_client.Histogram{
+				SampleSum:   floatPtr(sum),
+				SampleCount: uint64Ptr(count),
+			},
+		}
+	}
+
+	// each label set is averaged against its own previous value
+	require.NoError(t, m.UpdatePrometheusMetric("fetch_latency", histogram("Headers", 10, 10)))
+	require.NoError(t, m.UpdatePrometheusMetric("fetch_latency", histogram("Bodies", 100, 10)))
+	require.NoError(t, m.UpdatePrometheusMetric("fetch_latency", histogram("Headers", 30, 20)))
+	require.NoError(t, m.UpdatePrometheusMetric("fetch_latency", histogram("Bodies", 400, 20)))
+
+	require.Equal(t, 2.0, m.ExecutionMetrics["fetch_latency{table=Headers}"])
+	require.Equal(t, 30.0, m.ExecutionMetrics["fetch_latency{table=Bodies}"])
+	require.NotContains(t, m.ExecutionMetrics, "fetch_latency")
+}
+
 func floatPtr(f float64) *float64 {
 	return &f
 }
@@ -250,3 +281,7 @@ func floatPtr(f float64) *float64 {
 func uint64Ptr(u uint64) *uint64 {
 	return &u
 }
+
+func stringPtr(s string) *string {
+	return &s
+}
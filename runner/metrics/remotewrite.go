@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWriteMetricPrefix is prepended to the names of metrics pushed with
+// Prometheus remote-write.
+const RemoteWriteMetricPrefix = "base_bench_"
+
+// remoteWriteTimeout bounds a single remote-write request.
+const remoteWriteTimeout = 30 * time.Second
+
+// RemoteWriteMetricsWriter pushes the block metrics to a Prometheus
+// remote-write endpoint, e.g. Prometheus, Mimir or VictoriaMetrics. Each
+// metric becomes a series with one sample per block, timestamped with the
+// block's end time and labelled with the given labels.
+type RemoteWriteMetricsWriter struct {
+	URL    string
+	Labels map[string]string
+	client *http.Client
+}
+
+func NewRemoteWriteMetricsWriter(url string, labels map[string]string) *RemoteWriteMetricsWriter {
+	return &RemoteWriteMetricsWriter{
+		URL:    url,
+		Labels: labels,
+		client: &http.Client{Timeout: remoteWriteTimeout},
+	}
+}
+
+type remoteWriteSample struct {
+	value     float64
+	timestamp int64
+}
+
+type remoteWriteSeries struct {
+	labels  map[string]string
+	samples []remoteWriteSample
+}
+
+func (w *RemoteWriteMetricsWriter) Write(metrics []BlockMetrics) error {
+	series := w.series(metrics)
+	if len(series) == 0 {
+		return nil
+	}
+
+	body := snappy.Encode(nil, encodeWriteRequest(series))
+	ctx, cancel := context.WithTimeout(context.Background(), remoteWriteTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote-write request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to push metrics: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// series converts the block metrics to remote-write series, sorted by
+// metric. Samples within a series are ordered by time.
+func (w *RemoteWriteMetricsWriter) series(metrics []BlockMetrics) []remoteWriteSeries {
+	byKey := make(map[string]*remoteWriteSeries)
+	for i := range metrics {
+		m := &metrics[i]
+		timestamp := blockTime(m).UnixMilli()
+		for name := range m.ExecutionMetrics {
+			value, ok := m.GetMetricFloat(name)
+			if !ok || math.IsNaN(value) {
+				continue
+			}
+			s, ok := byKey[name]
+			if !ok {
+				s = &remoteWriteSeries{labels: w.seriesLabels(name)}
+				byKey[name] = s
+			}
+			s.samples = append(s.samples, remoteWriteSample{value: value, timestamp: timestamp})
+		}
+	}
+
+	series := make([]remoteWriteSeries, 0, len(byKey))
+	for _, key := range slices.Sorted(maps.Keys(byKey)) {
+		s := byKey[key]
+		slices.SortStableFunc(s.samples, func(a, b remoteWriteSample) int {
+			return cmp.Compare(a.timestamp, b.timestamp)
+		})
+		series = append(series, *s)
+	}
+	return series
+}
+
+// seriesLabels returns the labels of a metric, splitting the labels of a
+// series key like name{label=value} back out of its name.
+func (w *RemoteWriteMetricsWriter) seriesLabels(key string) map[string]string {
+	labels := make(map[string]string, len(w.Labels)+1)
+	for name, value := range w.Labels {
+		labels[sanitizeMetricName(name)] = value
+	}
+
+	name := key
+	if i := strings.IndexByte(key, '{'); i > 0 && strings.HasSuffix(key, "}") {
+		name = key[:i]
+		for _, pair := range strings.Split(key[i+1:len(key)-1], ",") {
+			label, value, ok := strings.Cut(pair, "=")
+			if ok {
+				labels[sanitizeMetricName(label)] = value
+			}
+		}
+	}
+	labels["__name__"] = RemoteWriteMetricPrefix + sanitizeMetricName(name)
+	return labels
+}
+
+// sanitizeMetricName replaces the characters not allowed in Prometheus metric
+// and label names, e.g. the slashes in "latency/new_payload", with
+// underscores.
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9':
+			// names cannot start with a digit
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// encodeWriteRequest encodes the series as a prometheus.WriteRequest protobuf
+// message.
+func encodeWriteRequest(series []remoteWriteSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for _, name := range slices.Sorted(maps.Keys(s.labels)) {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, s.labels[name])
+
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, sample := range s.samples {
+			var encoded []byte
+			encoded = protowire.AppendTag(encoded, 1, protowire.Fixed64Type)
+			encoded = protowire.AppendFixed64(encoded, math.Float64bits(sample.value))
+			encoded = protowire.AppendTag(encoded, 2, protowire.VarintType)
+			encoded = protowire.AppendVarint(encoded, uint64(sample.timestamp))
+
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, encoded)
+		}
+
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return req
+}
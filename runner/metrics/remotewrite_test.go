@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type decodedSeries struct {
+	labels  map[string]string
+	samples []remoteWriteSample
+}
+
+// decodeWriteRequest decodes a snappy-compressed prometheus.WriteRequest.
+func decodeWriteRequest(t *testing.T, body []byte) []decodedSeries {
+	data, err := snappy.Decode(nil, body)
+	require.NoError(t, err)
+
+	var series []decodedSeries
+	forEachField(t, data, func(num protowire.Number, ts []byte) {
+		require.Equal(t, protowire.Number(1), num)
+		s := decodedSeries{labels: make(map[string]string)}
+		forEachField(t, ts, func(num protowire.Number, value []byte) {
+			switch num {
+			case 1:
+				var name, labelValue string
+				forEachField(t, value, func(num protowire.Number, v []byte) {
+					if num == 1 {
+						name = string(v)
+					} else {
+						labelValue = string(v)
+					}
+				})
+				s.labels[name] = labelValue
+			case 2:
+				var sample remoteWriteSample
+				forEachField(t, value, func(num protowire.Number, v []byte) {
+					if num == 1 {
+						bits, _ := protowire.ConsumeFixed64(v)
+						sample.value = math.Float64frombits(bits)
+					} else {
+						ts, _ := protowire.ConsumeVarint(v)
+						sample.timestamp = int64(ts)
+					}
+				})
+				s.samples = append(s.samples, sample)
+			}
+		})
+		series = append(series, s)
+	})
+	return series
+}
+
+// forEachField calls fn with the number and raw value of each field of a
+// protobuf message. Length-delimited values are passed without their length.
+func forEachField(t *testing.T, data []byte, fn func(protowire.Number, []byte)) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		require.GreaterOrEqual(t, n, 0)
+		data = data[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		require.GreaterOrEqual(t, n, 0)
+		value := data[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		fn(num, value)
+		data = data[n:]
+	}
+}
+
+func TestRemoteWriteMetricsWriter(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	writer := NewRemoteWriteMetricsWriter(server.URL, map[string]string{"role": "sequencer"})
+	blocks := testBlockMetrics()
+	require.NoError(t, writer.Write(blocks))
+
+	series := decodeWriteRequest(t, body)
+	require.Len(t, series, 3)
+
+	require.Equal(t, map[string]string{"__name__": "base_bench_gas_per_second", "role": "sequencer"}, series[0].labels)
+	require.Equal(t, []remoteWriteSample{{value: 1.5e9, timestamp: blocks[0].EndTime.UnixMilli()}}, series[0].samples)
+
+	require.Equal(t, map[string]string{"__name__": "base_bench_latency_new_payload", "role": "sequencer"}, series[1].labels)
+	require.Equal(t, []remoteWriteSample{
+		{value: 0.002, timestamp: blocks[0].EndTime.UnixMilli()},
+		{value: 0.003, timestamp: blocks[1].EndTime.UnixMilli()},
+	}, series[1].samples)
+
+	// labels are split back out of series keys
+	require.Equal(t, map[string]string{"__name__": "base_bench_reth_db_table_size", "role": "sequencer", "table": "Headers"}, series[2].labels)
+}
+
+func TestRemoteWriteMetricsWriterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewRemoteWriteMetricsWriter(server.URL, nil).Write(testBlockMetrics())
+	require.ErrorContains(t, err, "out of order sample")
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	require.Equal(t, "latency_new_payload", sanitizeMetricName("latency/new_payload"))
+	require.Equal(t, "chain_execution_50_percentile", sanitizeMetricName("chain/execution.50-percentile"))
+	require.Equal(t, "_1m", sanitizeMetricName("1m"))
+}
@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ResourcesFileName is the name of the resource time series written to the
+// metrics directory.
+const ResourcesFileName = "resources.json"
+
+// Per-block resource metrics added to BlockMetrics.ExecutionMetrics.
+const (
+	ProcessCPUUsageMetric   = "process/cpu_usage"
+	ProcessRSSMetric        = "process/rss_bytes"
+	ProcessReadBytesMetric  = "process/read_bytes"
+	ProcessWriteBytesMetric = "process/write_bytes"
+	ProcessOpenFDsMetric    = "process/open_fds"
+	ProcessThreadsMetric    = "process/threads"
+)
+
+// clockTicks is the USER_HZ unit of the CPU times in /proc/<pid>/stat, which
+// is 100 on all common Linux platforms.
+const clockTicks = 100
+
+// ResourceSample is a snapshot of the resource usage of a process.
+type ResourceSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	// CPUSeconds is the total user and system CPU time used so far.
+	CPUSeconds float64 `json:"cpuSeconds"`
+	RSSBytes   uint64  `json:"rssBytes"`
+	// ReadBytes and WriteBytes are the total bytes read from and written to
+	// storage so far.
+	ReadBytes  uint64 `json:"readBytes"`
+	WriteBytes uint64 `json:"writeBytes"`
+	OpenFDs    int    `json:"openFds"`
+	Threads    int    `json:"threads"`
+}
+
+// ReadResourceSample reads the current resource usage of a process from /proc.
+func ReadResourceSample(pid int) (*ResourceSample, error) {
+	procDir := fmt.Sprintf("/proc/%d", pid)
+	sample := &ResourceSample{
+		Timestamp: time.Now(),
+	}
+
+	stat, err := os.ReadFile(path.Join(procDir, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	sample.CPUSeconds, sample.Threads, err = parseProcStat(string(stat))
+	if err != nil {
+		return nil, err
+	}
+
+	statm, err := os.ReadFile(path.Join(procDir, "statm"))
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return nil, errors.New("unexpected statm format")
+	}
+	residentPages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statm: %w", err)
+	}
+	sample.RSSBytes = residentPages * uint64(os.Getpagesize())
+
+	// io is only readable by the owner of the process
+	if io, err := os.ReadFile(path.Join(procDir, "io")); err == nil {
+		sample.ReadBytes, sample.WriteBytes = parseProcIO(string(io))
+	}
+
+	if fds, err := os.ReadDir(path.Join(procDir, "fd")); err == nil {
+		sample.OpenFDs = len(fds)
+	}
+
+	return sample, nil
+}
+
+// parseProcStat returns the total CPU time and thread count from the contents
+// of /proc/<pid>/stat.
+func parseProcStat(stat string) (float64, int, error) {
+	// the command name may contain spaces, so skip past its closing paren
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, 0, errors.New("unexpected stat format")
+	}
+	// fields after the command name start at field 3 (state)
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 18 {
+		return 0, 0, errors.New("unexpected stat format")
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse stime: %w", err)
+	}
+	threads, err := strconv.Atoi(fields[17])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse num_threads: %w", err)
+	}
+
+	return float64(utime+stime) / clockTicks, threads, nil
+}
+
+// parseProcIO returns the storage read and write bytes from the contents of
+// /proc/<pid>/io.
+func parseProcIO(io string) (uint64, uint64) {
+	var readBytes, writeBytes uint64
+	for _, line := range strings.Split(io, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "read_bytes":
+			readBytes = n
+		case "write_bytes":
+			writeBytes = n
+		}
+	}
+	return readBytes, writeBytes
+}
+
+// ResourceSampler samples the resource usage of a client process at a fixed
+// interval.
+type ResourceSampler struct {
+	log      log.Logger
+	pid      int
+	interval time.Duration
+
+	lock    sync.Mutex
+	samples []ResourceSample
+	// lastBlock is the index of the sample taken at the previous block.
+	lastBlock int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewResourceSampler creates a sampler for the process with the given PID.
+func NewResourceSampler(log log.Logger, pid int, interval time.Duration) *ResourceSampler {
+	return &ResourceSampler{
+		log:       log,
+		pid:       pid,
+		interval:  interval,
+		lastBlock: -1,
+	}
+}
+
+// Start samples the process in the background until Stop is called.
+func (s *ResourceSampler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.sample(); err != nil {
+				s.log.Warn("Failed to sample process resources, stopping sampler", "pid", s.pid, "err", err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops sampling and waits for the sampler to exit.
+func (s *ResourceSampler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+func (s *ResourceSampler) sample() (*ResourceSample, error) {
+	sample, err := ReadResourceSample(s.pid)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.samples = append(s.samples, *sample)
+	return sample, nil
+}
+
+// Samples returns the samples taken so far.
+func (s *ResourceSampler) Samples() []ResourceSample {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]ResourceSample(nil), s.samples...)
+}
+
+// AddBlockMetrics takes a sample and adds the resource usage since the
+// previous block to the block metrics: CPU usage in cores, read and write
+// bytes, and the peak RSS, open FDs and threads.
+func (s *ResourceSampler) AddBlockMetrics(m *BlockMetrics) error {
+	if _, err := s.sample(); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current := len(s.samples) - 1
+	start := s.lastBlock
+	s.lastBlock = current
+	// the first block has no previous sample to diff against
+	if start < 0 {
+		return nil
+	}
+
+	first := s.samples[start]
+	last := s.samples[current]
+	if elapsed := last.Timestamp.Sub(first.Timestamp).Seconds(); elapsed > 0 {
+		m.AddExecutionMetric(ProcessCPUUsageMetric, (last.CPUSeconds-first.CPUSeconds)/elapsed)
+	}
+	m.AddExecutionMetric(ProcessReadBytesMetric, float64(last.ReadBytes-first.ReadBytes))
+	m.AddExecutionMetric(ProcessWriteBytesMetric, float64(last.WriteBytes-first.WriteBytes))
+
+	var rss uint64
+	var fds, threads int
+	for _, sample := range s.samples[start+1 : current+1] {
+		rss = max(rss, sample.RSSBytes)
+		fds = max(fds, sample.OpenFDs)
+		threads = max(threads, sample.Threads)
+	}
+	m.AddExecutionMetric(ProcessRSSMetric, float64(rss))
+	m.AddExecutionMetric(ProcessOpenFDsMetric, float64(fds))
+	m.AddExecutionMetric(ProcessThreadsMetric, float64(threads))
+	return nil
+}
+
+// Write writes the samples to resources.json in the given directory.
+func (s *ResourceSampler) Write(baseDir string) error {
+	data, err := json.MarshalIndent(s.Samples(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource samples: %w", err)
+	}
+
+	if err := os.WriteFile(path.Join(baseDir, ResourcesFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write resource samples: %w", err)
+	}
+	return nil
+}
+
+type resourceCollector struct {
+	Collector
+	log     log.Logger
+	sampler *ResourceSampler
+}
+
+// NewResourceCollector wraps a collector so that the resource usage of the
+// sampled process is added to every block's metrics.
+func NewResourceCollector(log log.Logger, collector Collector, sampler *ResourceSampler) Collector {
+	return &resourceCollector{
+		Collector: collector,
+		log:       log,
+		sampler:   sampler,
+	}
+}
+
+func (r *resourceCollector) Collect(ctx context.Context, m *BlockMetrics) error {
+	if err := r.sampler.AddBlockMetrics(m); err != nil {
+		r.log.Warn("Failed to sample process resources", "err", err)
+	}
+	return r.Collector.Collect(ctx, m)
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStat(t *testing.T) {
+	stat := "1234 (op reth (x)) S 1 1234 1234 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 42 0 100 0 0"
+	cpuSeconds, threads, err := parseProcStat(stat)
+	require.NoError(t, err)
+	require.InDelta(t, 3.0, cpuSeconds, 1e-9)
+	require.Equal(t, 42, threads)
+
+	_, _, err = parseProcStat("1234 (geth) S 1")
+	require.Error(t, err)
+}
+
+func TestParseProcIO(t *testing.T) {
+	io := "rchar: 100\nwchar: 200\nsyscr: 1\nsyscw: 2\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n"
+	readBytes, writeBytes := parseProcIO(io)
+	require.Equal(t, uint64(4096), readBytes)
+	require.Equal(t, uint64(8192), writeBytes)
+}
+
+func TestResourceSampler(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("/proc is not available")
+	}
+
+	sampler := NewResourceSampler(log.New(), os.Getpid(), 10*time.Millisecond)
+	sampler.Start(context.Background())
+
+	first := NewBlockMetrics()
+	require.NoError(t, sampler.AddBlockMetrics(first))
+	require.Empty(t, first.ExecutionMetrics, "first block has no previous sample")
+
+	time.Sleep(50 * time.Millisecond)
+	second := NewBlockMetrics()
+	require.NoError(t, sampler.AddBlockMetrics(second))
+	sampler.Stop()
+
+	rss, ok := second.GetMetricFloat(ProcessRSSMetric)
+	require.True(t, ok)
+	require.Greater(t, rss, 0.0)
+	threads, ok := second.GetMetricFloat(ProcessThreadsMetric)
+	require.True(t, ok)
+	require.GreaterOrEqual(t, threads, 1.0)
+	_, ok = second.GetMetricFloat(ProcessCPUUsageMetric)
+	require.True(t, ok)
+
+	require.GreaterOrEqual(t, len(sampler.Samples()), 3)
+
+	dir := t.TempDir()
+	require.NoError(t, sampler.Write(dir))
+	require.FileExists(t, dir+"/"+ResourcesFileName)
+}
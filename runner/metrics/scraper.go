@@ -0,0 +1,343 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/common/expfmt"
+)
+
+// MetricsSeriesFileName is the name of the scraped metrics time series
+// written to the metrics directory.
+const MetricsSeriesFileName = "metrics-series.json"
+
+// SeriesSample is the value of a scraped series at the time of a scrape.
+type SeriesSample struct {
+	// Series identifies the scraped series by its name and labels.
+	Series string `json:"series"`
+	// Key is the name the series is recorded under. Series with the same key
+	// are combined using Aggregate.
+	Key       string `json:"key"`
+	Aggregate string `json:"aggregate,omitempty"`
+	// Value is set for gauges, counters and JSON metrics.
+	Value *float64 `json:"value,omitempty"`
+	// Sum and Count are the cumulative sum and count of a histogram or
+	// summary.
+	Sum   *float64 `json:"sum,omitempty"`
+	Count *float64 `json:"count,omitempty"`
+}
+
+// Scrape is a timestamped scrape of a client's metrics.
+type Scrape struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Series    []SeriesSample `json:"series"`
+}
+
+// Scraper scrapes the selected metrics of a client.
+type Scraper interface {
+	Scrape(ctx context.Context) ([]SeriesSample, error)
+}
+
+func fetchMetrics(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics response: %w", err)
+	}
+	return body, nil
+}
+
+type prometheusScraper struct {
+	url       string
+	selection MetricSelection
+}
+
+// NewPrometheusScraper scrapes the selected series from a Prometheus text
+// endpoint.
+func NewPrometheusScraper(url string, selection MetricSelection) Scraper {
+	return &prometheusScraper{
+		url:       url,
+		selection: selection,
+	}
+}
+
+func (p *prometheusScraper) Scrape(ctx context.Context) ([]SeriesSample, error) {
+	body, err := fetchMetrics(ctx, p.url)
+	if err != nil {
+		return nil, err
+	}
+
+	txtParser := expfmt.TextParser{}
+	families, err := txtParser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	return p.selection.PrometheusSamples(families), nil
+}
+
+type jsonScraper struct {
+	url       string
+	selection MetricSelection
+}
+
+// NewJSONScraper scrapes the selected metrics from an endpoint serving a flat
+// JSON object of metric values, like geth's /debug/metrics.
+func NewJSONScraper(url string, selection MetricSelection) Scraper {
+	return &jsonScraper{
+		url:       url,
+		selection: selection,
+	}
+}
+
+func (j *jsonScraper) Scrape(ctx context.Context) ([]SeriesSample, error) {
+	body, err := fetchMetrics(ctx, j.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var metricsData map[string]interface{}
+	if err := json.Unmarshal(body, &metricsData); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics: %w", err)
+	}
+	return j.selection.JSONSamples(metricsData), nil
+}
+
+type scrapedBlock struct {
+	metrics BlockMetrics
+	start   time.Time
+	end     time.Time
+}
+
+// ScrapingCollector scrapes a client's metrics in the background at a fixed
+// interval, so that scraping does not slow down the benchmark loop. Block
+// values are derived afterwards from the scrapes taken around each block.
+type ScrapingCollector struct {
+	log      log.Logger
+	scraper  Scraper
+	interval time.Duration
+
+	lock    sync.Mutex
+	scrapes []Scrape
+	blocks  []scrapedBlock
+	// lastCollect is the end of the previous block, used as the start of
+	// blocks without a StartTime.
+	lastCollect time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScrapingCollector creates a collector for a client's metrics. With an
+// interval of zero, metrics are scraped once at the end of every block
+// instead.
+func NewScrapingCollector(log log.Logger, scraper Scraper, interval time.Duration) *ScrapingCollector {
+	return &ScrapingCollector{
+		log:      log,
+		scraper:  scraper,
+		interval: interval,
+	}
+}
+
+// Start takes a baseline scrape and, if an interval is set, scrapes in the
+// background until Stop is called.
+func (c *ScrapingCollector) Start(ctx context.Context) {
+	c.lastCollect = time.Now()
+	c.scrape(ctx)
+	if c.interval <= 0 {
+		return
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.scrape(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops scraping and waits for the scraper to exit.
+func (c *ScrapingCollector) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	<-c.done
+}
+
+func (c *ScrapingCollector) scrape(ctx context.Context) {
+	series, err := c.scraper.Scrape(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.log.Warn("Failed to scrape metrics", "err", err)
+		}
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.scrapes = append(c.scrapes, Scrape{
+		Timestamp: time.Now(),
+		Series:    series,
+	})
+}
+
+// Collect records the block. Its client metrics are derived in GetMetrics.
+func (c *ScrapingCollector) Collect(ctx context.Context, m *BlockMetrics) error {
+	if c.interval <= 0 {
+		c.scrape(ctx)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	block := scrapedBlock{
+		metrics: *m.Copy(),
+		start:   m.StartTime,
+		end:     m.EndTime,
+	}
+	if block.start.IsZero() {
+		block.start = c.lastCollect
+	}
+	if block.end.IsZero() {
+		block.end = time.Now()
+	}
+	c.lastCollect = time.Now()
+	c.blocks = append(c.blocks, block)
+	return nil
+}
+
+// Scrapes returns the scrapes taken so far.
+func (c *ScrapingCollector) Scrapes() []Scrape {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]Scrape(nil), c.scrapes...)
+}
+
+// GetMetrics returns the collected blocks with the client metrics derived
+// from the last scrape at or before each block's start and the first scrape
+// at or after its end.
+func (c *ScrapingCollector) GetMetrics() []BlockMetrics {
+	// make sure the last block is followed by a scrape
+	c.lock.Lock()
+	needsScrape := len(c.blocks) > 0 && (len(c.scrapes) == 0 || c.scrapes[len(c.scrapes)-1].Timestamp.Before(c.blocks[len(c.blocks)-1].end))
+	c.lock.Unlock()
+	if needsScrape {
+		c.scrape(context.Background())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	blocks := make([]BlockMetrics, len(c.blocks))
+	for i, block := range c.blocks {
+		m := block.metrics.Copy()
+		after := sort.Search(len(c.scrapes), func(j int) bool {
+			return !c.scrapes[j].Timestamp.Before(block.end)
+		})
+		if after < len(c.scrapes) {
+			var before *Scrape
+			if j := sort.Search(len(c.scrapes), func(j int) bool {
+				return c.scrapes[j].Timestamp.After(block.start)
+			}); j > 0 {
+				before = &c.scrapes[j-1]
+			}
+			for key, value := range blockValues(before, &c.scrapes[after]) {
+				m.AddExecutionMetric(key, value)
+			}
+		}
+		blocks[i] = *m
+	}
+	return blocks
+}
+
+// Write writes the scrapes to metrics-series.json in the given directory.
+func (c *ScrapingCollector) Write(baseDir string) error {
+	data, err := json.MarshalIndent(c.Scrapes(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics series: %w", err)
+	}
+
+	if err := os.WriteFile(path.Join(baseDir, MetricsSeriesFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write metrics series: %w", err)
+	}
+	return nil
+}
+
+// blockValues returns the metric values between two scrapes. Gauges and
+// counters take their value at the later scrape, histograms and summaries the
+// average of the observations in between. Without an earlier scrape,
+// histograms are averaged over all observations.
+func blockValues(before *Scrape, after *Scrape) map[string]float64 {
+	previous := make(map[string]SeriesSample)
+	if before != nil {
+		for _, sample := range before.Series {
+			previous[sample.Series] = sample
+		}
+	}
+
+	values := make(map[string]float64)
+	for _, sample := range after.Series {
+		var value float64
+		if sample.Value != nil {
+			value = *sample.Value
+		} else {
+			prevSum, prevCount := 0.0, 0.0
+			if prev, ok := previous[sample.Series]; ok && prev.Sum != nil {
+				prevSum, prevCount = *prev.Sum, *prev.Count
+			}
+			deltaCount := *sample.Count - prevCount
+			if deltaCount == 0 {
+				continue
+			}
+			value = (*sample.Sum - prevSum) / deltaCount
+			if math.IsNaN(value) {
+				continue
+			}
+		}
+
+		current, seen := values[sample.Key]
+		switch {
+		case sample.Aggregate == "" || !seen:
+			values[sample.Key] = value
+		case sample.Aggregate == AggregateSum:
+			values[sample.Key] = current + value
+		case sample.Aggregate == AggregateMax:
+			values[sample.Key] = max(current, value)
+		}
+	}
+	return values
+}
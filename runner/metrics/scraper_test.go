@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScraper returns a histogram and a gauge that are updated by the test.
+type fakeScraper struct {
+	lock  sync.Mutex
+	sum   float64
+	count float64
+	gauge float64
+}
+
+func (f *fakeScraper) set(sum float64, count float64, gauge float64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.sum, f.count, f.gauge = sum, count, gauge
+}
+
+func (f *fakeScraper) Scrape(ctx context.Context) ([]SeriesSample, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	sum, count, gauge := f.sum, f.count, f.gauge
+	return []SeriesSample{
+		{Series: "latency", Key: "latency", Sum: &sum, Count: &count},
+		{Series: "size", Key: "size", Value: &gauge},
+	}, nil
+}
+
+func TestScrapingCollectorPerBlock(t *testing.T) {
+	scraper := &fakeScraper{}
+	collector := NewScrapingCollector(log.New(), scraper, 0)
+	collector.Start(context.Background())
+	defer collector.Stop()
+
+	m := NewBlockMetrics()
+	for i, state := range [][3]float64{{10, 5, 100}, {40, 10, 150}, {40, 10, 160}} {
+		m.SetBlockNumber(uint64(i))
+		m.StartTime = time.Now()
+		scraper.set(state[0], state[1], state[2])
+		m.EndTime = time.Now()
+		require.NoError(t, collector.Collect(context.Background(), m))
+	}
+
+	blocks := collector.GetMetrics()
+	require.Len(t, blocks, 3)
+	require.Equal(t, map[string]interface{}{"latency": 2.0, "size": 100.0}, blocks[0].ExecutionMetrics)
+	require.Equal(t, map[string]interface{}{"latency": 6.0, "size": 150.0}, blocks[1].ExecutionMetrics)
+	// no observations in the last block
+	require.Equal(t, map[string]interface{}{"size": 160.0}, blocks[2].ExecutionMetrics)
+	require.Len(t, collector.Scrapes(), 4)
+}
+
+func TestScrapingCollectorBackground(t *testing.T) {
+	scraper := &fakeScraper{}
+	collector := NewScrapingCollector(log.New(), scraper, time.Millisecond)
+	collector.Start(context.Background())
+
+	m := NewBlockMetrics()
+	m.StartTime = time.Now()
+	scraper.set(30, 10, 42)
+	m.EndTime = time.Now()
+	require.NoError(t, collector.Collect(context.Background(), m))
+	require.Eventually(t, func() bool {
+		return len(collector.Scrapes()) > 3
+	}, time.Second, time.Millisecond)
+	collector.Stop()
+
+	blocks := collector.GetMetrics()
+	require.Len(t, blocks, 1)
+	require.Equal(t, map[string]interface{}{"latency": 3.0, "size": 42.0}, blocks[0].ExecutionMetrics)
+
+	require.NoError(t, collector.Write(t.TempDir()))
+}
+
+func TestBlockValuesAggregate(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	before := &Scrape{Series: []SeriesSample{
+		{Series: "latency{table=a}", Key: "latency", Aggregate: AggregateMax, Sum: value(10), Count: value(10)},
+		{Series: "latency{table=b}", Key: "latency", Aggregate: AggregateMax, Sum: value(10), Count: value(10)},
+	}}
+	after := &Scrape{Series: []SeriesSample{
+		{Series: "latency{table=a}", Key: "latency", Aggregate: AggregateMax, Sum: value(20), Count: value(20)},
+		{Series: "latency{table=b}", Key: "latency", Aggregate: AggregateMax, Sum: value(40), Count: value(20)},
+		{Series: "size{table=a}", Key: "size", Aggregate: AggregateSum, Value: value(1)},
+		{Series: "size{table=b}", Key: "size", Aggregate: AggregateSum, Value: value(2)},
+	}}
+	require.Equal(t, map[string]float64{"latency": 3.0, "size": 3.0}, blockValues(before, after))
+}
+
+func TestPrometheusScraper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`# TYPE reth_sync_execution_execution_duration histogram
+reth_sync_execution_execution_duration_bucket{le="+Inf"} 4
+reth_sync_execution_execution_duration_sum 2
+reth_sync_execution_execution_duration_count 4
+# TYPE reth_other gauge
+reth_other 1
+`))
+	}))
+	defer server.Close()
+
+	scraper := NewPrometheusScraper(server.URL, NewMetricSelection("reth_sync_execution_execution_duration"))
+	samples, err := scraper.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	require.Equal(t, "reth_sync_execution_execution_duration", samples[0].Key)
+	require.Equal(t, 2.0, *samples[0].Sum)
+	require.Equal(t, 4.0, *samples[0].Count)
+}
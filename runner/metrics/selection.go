@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"gopkg.in/yaml.v3"
+)
+
+// Aggregations of the series of a labelled metric.
+const (
+	AggregateSum = "sum"
+	AggregateMax = "max"
+)
+
+// MetricRule selects metrics scraped from a client.
+type MetricRule struct {
+	// Name is the scraped metric name. "*" matches any sequence of
+	// characters.
+	Name string `yaml:"name"`
+	// Labels only selects series with these label values. Metrics without
+	// labels, e.g. geth's JSON metrics, never match a rule with labels.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Rename records the metric under a different name. It cannot be used
+	// with a wildcard name.
+	Rename string `yaml:"rename,omitempty"`
+	// Aggregate combines the selected series of a labelled Prometheus metric
+	// into a single value using "sum" or "max". Otherwise each series is
+	// recorded as name{label=value}, leaving out the labels fixed by Labels.
+	Aggregate string `yaml:"aggregate,omitempty"`
+}
+
+// Check validates the rule.
+func (r *MetricRule) Check() error {
+	if r.Name == "" {
+		return errors.New("metric name is required")
+	}
+	if r.Rename != "" && strings.Contains(r.Name, "*") {
+		return fmt.Errorf("metric %s: rename cannot be used with a wildcard name", r.Name)
+	}
+	switch r.Aggregate {
+	case "", AggregateSum, AggregateMax:
+	default:
+		return fmt.Errorf("metric %s: unknown aggregation %q, expected sum or max", r.Name, r.Aggregate)
+	}
+	return nil
+}
+
+// matches returns whether the rule selects a scraped metric.
+func (r *MetricRule) matches(name string, labels map[string]string) bool {
+	if !matchName(r.Name, name) {
+		return false
+	}
+	for key, value := range r.Labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns the name to record a selected metric under: the metric's name
+// or Rename, followed by the labels that are not fixed by the rule.
+func (r *MetricRule) key(name string, labels map[string]string) string {
+	if r.Rename != "" {
+		name = r.Rename
+	}
+	if r.Aggregate != "" {
+		return name
+	}
+	free := make(map[string]string, len(labels))
+	for label, value := range labels {
+		if _, ok := r.Labels[label]; !ok {
+			free[label] = value
+		}
+	}
+	return SeriesKey(name, free)
+}
+
+func matchName(pattern string, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	// match the parts between wildcards as early as possible, leaving the
+	// rest of the name for the last part
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
+
+// MetricSelection is the list of metrics scraped from a client.
+type MetricSelection []MetricRule
+
+// NewMetricSelection selects the metrics with the given names.
+func NewMetricSelection(names ...string) MetricSelection {
+	selection := make(MetricSelection, len(names))
+	for i, name := range names {
+		selection[i] = MetricRule{Name: name}
+	}
+	return selection
+}
+
+// Check validates every rule in the selection.
+func (s MetricSelection) Check() error {
+	for i := range s {
+		if err := s[i].Check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule that selects a scraped metric.
+func (s MetricSelection) Match(name string, labels map[string]string) (*MetricRule, bool) {
+	for i := range s {
+		if s[i].matches(name, labels) {
+			return &s[i], true
+		}
+	}
+	return nil, false
+}
+
+// JSONSamples returns the selected metrics from a flat map of metric values.
+func (s MetricSelection) JSONSamples(values map[string]interface{}) []SeriesSample {
+	samples := make([]SeriesSample, 0)
+	for name, value := range values {
+		rule, ok := s.Match(name, nil)
+		if !ok {
+			continue
+		}
+		if v, ok := value.(float64); ok && !math.IsNaN(v) {
+			samples = append(samples, SeriesSample{
+				Series: name,
+				Key:    rule.key(name, nil),
+				Value:  &v,
+			})
+		}
+	}
+	return samples
+}
+
+// PrometheusSamples returns the selected series from parsed Prometheus metric
+// families.
+func (s MetricSelection) PrometheusSamples(families map[string]*io_prometheus_client.MetricFamily) []SeriesSample {
+	samples := make([]SeriesSample, 0)
+	for name, family := range families {
+		for _, series := range family.GetMetric() {
+			labels := seriesLabels(series)
+			rule, ok := s.Match(name, labels)
+			if !ok {
+				continue
+			}
+
+			sample := SeriesSample{
+				Series:    SeriesKey(name, labels),
+				Key:       rule.key(name, labels),
+				Aggregate: rule.Aggregate,
+			}
+			switch {
+			case series.Histogram != nil:
+				sum := series.Histogram.GetSampleSum()
+				count := float64(series.Histogram.GetSampleCount())
+				sample.Sum, sample.Count = &sum, &count
+			case series.Summary != nil:
+				sum := series.Summary.GetSampleSum()
+				count := float64(series.Summary.GetSampleCount())
+				sample.Sum, sample.Count = &sum, &count
+			case series.Gauge != nil && series.Gauge.Value != nil:
+				sample.Value = series.Gauge.Value
+			case series.Counter != nil && series.Counter.Value != nil:
+				sample.Value = series.Counter.Value
+			case series.Untyped != nil && series.Untyped.Value != nil:
+				sample.Value = series.Untyped.Value
+			}
+			// NaN values and nil values are silently omitted
+			if sample.Value != nil && math.IsNaN(*sample.Value) {
+				continue
+			}
+			if sample.Value == nil && sample.Sum == nil {
+				continue
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// MetricsProfile maps node types to the metrics scraped from them. Clients
+// not in the profile use their built-in selection.
+type MetricsProfile map[string]MetricSelection
+
+// LoadMetricsProfile reads a metrics profile from a YAML file.
+func LoadMetricsProfile(path string) (MetricsProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics profile: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var profile MetricsProfile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics profile %s: %w", path, err)
+	}
+	if err := profile.Check(); err != nil {
+		return nil, fmt.Errorf("invalid metrics profile %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// Check validates the selection of every client in the profile.
+func (p MetricsProfile) Check() error {
+	for nodeType, selection := range p {
+		if len(selection) == 0 {
+			return fmt.Errorf("%s: at least one metric is required", nodeType)
+		}
+		if err := selection.Check(); err != nil {
+			return fmt.Errorf("%s: %w", nodeType, err)
+		}
+	}
+	return nil
+}
+
+// Selection returns the metrics to scrape from a client, or defaults if the
+// profile does not configure it.
+func (p MetricsProfile) Selection(nodeType string, defaults MetricSelection) MetricSelection {
+	if selection, ok := p[nodeType]; ok {
+		return selection
+	}
+	return defaults
+}
+
+// With returns a copy of the profile with the clients in overrides replaced.
+func (p MetricsProfile) With(overrides MetricsProfile) MetricsProfile {
+	profile := make(MetricsProfile, len(p)+len(overrides))
+	for nodeType, selection := range p {
+		profile[nodeType] = selection
+	}
+	for nodeType, selection := range overrides {
+		profile[nodeType] = selection
+	}
+	return profile
+}
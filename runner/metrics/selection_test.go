@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricSelectionMatch(t *testing.T) {
+	selection := MetricSelection{
+		{Name: "reth_sync_execution_execution_duration"},
+		{Name: "reth_sync_state_provider_*_latency"},
+		{Name: "reth_db_table_size", Labels: map[string]string{"table": "PlainStorageState"}, Rename: "db/storage_size"},
+	}
+	require.NoError(t, selection.Check())
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		key    string
+		ok     bool
+	}{
+		{name: "reth_sync_execution_execution_duration", key: "reth_sync_execution_execution_duration", ok: true},
+		{name: "reth_sync_execution_execution_duration_count"},
+		{name: "reth_sync_state_provider_storage_fetch_latency", key: "reth_sync_state_provider_storage_fetch_latency", ok: true},
+		{name: "reth_sync_state_provider_storage_fetch_count"},
+		{name: "reth_db_table_size", labels: map[string]string{"table": "PlainStorageState"}, key: "db/storage_size", ok: true},
+		{name: "reth_db_table_size", labels: map[string]string{"table": "Headers"}},
+		{name: "reth_db_table_size"},
+	}
+	for _, tt := range tests {
+		rule, ok := selection.Match(tt.name, tt.labels)
+		require.Equal(t, tt.ok, ok, tt.name)
+		if ok {
+			require.Equal(t, tt.key, rule.key(tt.name, tt.labels), tt.name)
+		}
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	require.True(t, matchName("chain/*", "chain/execution.50-percentile"))
+	require.True(t, matchName("chain/*.95-percentile", "chain/account/reads.95-percentile"))
+	require.True(t, matchName("*a*a*", "aa"))
+	require.False(t, matchName("*a*a*", "a"))
+	require.False(t, matchName("chain/*.95-percentile", "chain/execution.50-percentile"))
+}
+
+func TestMetricRuleCheck(t *testing.T) {
+	require.Error(t, (&MetricRule{}).Check())
+	require.Error(t, (&MetricRule{Name: "chain/*", Rename: "chain"}).Check())
+	require.Error(t, (&MetricRule{Name: "reth_db_table_size", Aggregate: "avg"}).Check())
+	require.NoError(t, (&MetricRule{Name: "reth_db_table_size", Aggregate: AggregateMax}).Check())
+}
+
+func TestPrometheusSamples(t *testing.T) {
+	text := `# TYPE reth_db_table_size gauge
+reth_db_table_size{table="Headers"} 100
+reth_db_table_size{table="PlainStorageState"} 2000
+# TYPE reth_sync_execution_execution_duration gauge
+reth_sync_execution_execution_duration 0.5
+# TYPE reth_other gauge
+reth_other 1
+`
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	require.NoError(t, err)
+
+	selection := MetricSelection{
+		{Name: "reth_sync_execution_execution_duration"},
+		{Name: "reth_db_table_size", Labels: map[string]string{"table": "PlainStorageState"}, Rename: "db/storage_size"},
+	}
+	values := blockValues(nil, &Scrape{Series: selection.PrometheusSamples(families)})
+	require.Equal(t, map[string]float64{
+		"reth_sync_execution_execution_duration": 0.5,
+		"db/storage_size":                        2000.0,
+	}, values)
+}
+
+func TestPrometheusSamplesLabels(t *testing.T) {
+	text := `# TYPE reth_db_table_size gauge
+reth_db_table_size{db="state",table="Headers"} 100
+reth_db_table_size{db="state",table="PlainStorageState"} 2000
+reth_db_table_size{db="static",table="Headers"} 300
+# TYPE reth_db_table_pages gauge
+reth_db_table_pages{table="Headers"} 10
+reth_db_table_pages{table="PlainStorageState"} 50
+# TYPE reth_db_table_entries gauge
+reth_db_table_entries{table="Headers"} 7
+reth_db_table_entries{table="PlainStorageState"} 3
+`
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	require.NoError(t, err)
+
+	selection := MetricSelection{
+		{Name: "reth_db_table_size", Labels: map[string]string{"db": "state"}},
+		{Name: "reth_db_table_pages", Aggregate: AggregateSum},
+		{Name: "reth_db_table_entries", Aggregate: AggregateMax, Rename: "db/max_entries"},
+	}
+	values := blockValues(nil, &Scrape{Series: selection.PrometheusSamples(families)})
+	require.Equal(t, map[string]float64{
+		"reth_db_table_size{table=Headers}":           100.0,
+		"reth_db_table_size{table=PlainStorageState}": 2000.0,
+		"reth_db_table_pages":                         60.0,
+		"db/max_entries":                              7.0,
+	}, values)
+}
+
+func TestLoadMetricsProfile(t *testing.T) {
+	dir := t.TempDir()
+	profilePath := filepath.Join(dir, "profile.yml")
+	require.NoError(t, os.WriteFile(profilePath, []byte(`geth:
+  - name: chain/execution.95-percentile
+    rename: chain/execution.p95
+reth:
+  - name: reth_sync_*
+`), 0644))
+
+	profile, err := LoadMetricsProfile(profilePath)
+	require.NoError(t, err)
+
+	defaults := NewMetricSelection("default")
+	require.Equal(t, MetricSelection{{Name: "chain/execution.95-percentile", Rename: "chain/execution.p95"}}, profile.Selection("geth", defaults))
+	require.Equal(t, defaults, profile.Selection("rbuilder", defaults))
+
+	overridden := profile.With(MetricsProfile{"reth": NewMetricSelection("reth_other")})
+	require.Equal(t, NewMetricSelection("reth_other"), overridden.Selection("reth", defaults))
+	require.Equal(t, NewMetricSelection("reth_sync_*"), profile.Selection("reth", defaults))
+
+	require.NoError(t, os.WriteFile(profilePath, []byte(`reth:
+  - nme: reth_sync_*
+`), 0644))
+	_, err = LoadMetricsProfile(profilePath)
+	require.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// Distribution summarizes the distribution of a metric across blocks.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+// NewDistribution computes a distribution summary for the given samples.
+func NewDistribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mean := Mean(sorted)
+
+	return Distribution{
+		Count:  len(sorted),
+		Mean:   mean,
+		StdDev: StdDev(sorted),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		P50:    percentileSorted(sorted, 50),
+		P90:    percentileSorted(sorted, 90),
+		P99:    percentileSorted(sorted, 99),
+	}
+}
+
+// Mean returns the arithmetic mean of the values.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// StdDev returns the sample standard deviation of the values.
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := Mean(values)
+	var sumSquares float64
+	for _, v := range values {
+		sumSquares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
+
+// Percentile returns the p-th percentile (0-100) of the values using linear
+// interpolation between the closest ranks.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return percentileSorted(sorted, p)
+}
+
+func percentileSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	weight := rank - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
+
+// GetMetricValues returns the value of the named metric for every block that
+// recorded it.
+func GetMetricValues(metrics []BlockMetrics, name string) []float64 {
+	values := make([]float64, 0, len(metrics))
+	for _, m := range metrics {
+		if value, ok := m.GetMetricFloat(name); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// MannWhitneyU performs a two-sided Mann-Whitney U test on two independent
+// samples and returns the p-value using the normal approximation with tie
+// correction. It makes no assumption about the shape of the distributions,
+// which suits long-tailed latency samples.
+func MannWhitneyU(a []float64, b []float64) float64 {
+	n1 := len(a)
+	n2 := len(b)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		value float64
+		first bool
+	}
+	combined := make([]sample, 0, n1+n2)
+	for _, v := range a {
+		combined = append(combined, sample{value: v, first: true})
+	}
+	for _, v := range b {
+		combined = append(combined, sample{value: v, first: false})
+	}
+	sort.Slice(combined, func(i, j int) bool {
+		return combined[i].value < combined[j].value
+	})
+
+	// assign average ranks to ties and accumulate the tie correction term
+	n := float64(n1 + n2)
+	var rankSumA float64
+	var tieCorrection float64
+	for i := 0; i < len(combined); {
+		j := i
+		for j < len(combined) && combined[j].value == combined[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if combined[k].first {
+				rankSumA += rank
+			}
+		}
+		ties := float64(j - i)
+		tieCorrection += ties*ties*ties - ties
+		i = j
+	}
+
+	u := rankSumA - float64(n1)*float64(n1+1)/2
+	mu := float64(n1) * float64(n2) / 2
+	sigma := math.Sqrt(float64(n1) * float64(n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1))))
+	if sigma == 0 || math.IsNaN(sigma) {
+		return 1
+	}
+
+	// continuity correction
+	diff := math.Abs(u-mu) - 0.5
+	if diff < 0 {
+		diff = 0
+	}
+	z := diff / sigma
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// tCritical95 holds the two-sided 95% critical values of Student's
+// t-distribution for 1 to 30 degrees of freedom.
+var tCritical95 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// ConfidenceInterval95 returns the mean of the values and the bounds of its
+// two-sided 95% confidence interval using Student's t-distribution. With a
+// single sample the interval collapses to the mean.
+func ConfidenceInterval95(values []float64) (mean float64, lower float64, upper float64) {
+	mean = Mean(values)
+	if len(values) < 2 {
+		return mean, mean, mean
+	}
+
+	df := len(values) - 1
+	t := 1.96
+	if df <= len(tCritical95) {
+		t = tCritical95[df-1]
+	}
+
+	margin := t * StdDev(values) / math.Sqrt(float64(len(values)))
+	return mean, mean - margin, mean + margin
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDistribution(t *testing.T) {
+	values := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		values = append(values, float64(i))
+	}
+
+	d := NewDistribution(values)
+	require.Equal(t, 100, d.Count)
+	require.Equal(t, 1.0, d.Min)
+	require.Equal(t, 100.0, d.Max)
+	require.InDelta(t, 50.5, d.Mean, 1e-9)
+	require.InDelta(t, 50.5, d.P50, 1e-9)
+	require.InDelta(t, 90.1, d.P90, 1e-9)
+	require.InDelta(t, 99.01, d.P99, 1e-9)
+	require.InDelta(t, 29.011, d.StdDev, 1e-3)
+
+	// input must not be reordered
+	require.Equal(t, 100.0, values[0])
+}
+
+func TestNewDistribution_Empty(t *testing.T) {
+	require.Equal(t, Distribution{}, NewDistribution(nil))
+
+	d := NewDistribution([]float64{3})
+	require.Equal(t, Distribution{Count: 1, Mean: 3, Min: 3, Max: 3, P50: 3, P90: 3, P99: 3}, d)
+}
+
+func TestGetMetricValues(t *testing.T) {
+	a := NewBlockMetrics()
+	a.AddExecutionMetric("latency", 2*time.Second)
+	b := NewBlockMetrics()
+	b.AddExecutionMetric("other", 1.0)
+	c := NewBlockMetrics()
+	c.AddExecutionMetric("latency", 500*time.Millisecond)
+
+	values := GetMetricValues([]BlockMetrics{*a, *b, *c}, "latency")
+	require.Equal(t, []float64{2, 0.5}, values)
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	b := []float64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+
+	require.Less(t, MannWhitneyU(a, b), 0.001)
+	require.Less(t, MannWhitneyU(b, a), 0.001)
+	require.InDelta(t, 1.0, MannWhitneyU(a, a), 1e-9)
+	require.Equal(t, 1.0, MannWhitneyU(a, nil))
+	require.Equal(t, 1.0, MannWhitneyU([]float64{1, 1}, []float64{1, 1}))
+}
+
+func TestConfidenceInterval95(t *testing.T) {
+	mean, lower, upper := ConfidenceInterval95([]float64{10, 12, 14})
+	require.InDelta(t, 12, mean, 1e-9)
+	// stddev 2, n 3, t(2) = 4.303
+	margin := 4.303 * 2 / 1.7320508
+	require.InDelta(t, 12-margin, lower, 1e-3)
+	require.InDelta(t, 12+margin, upper, 1e-3)
+
+	mean, lower, upper = ConfidenceInterval95([]float64{5})
+	require.Equal(t, []float64{5, 5, 5}, []float64{mean, lower, upper})
+}
@@ -0,0 +1,204 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Additional metrics output formats. JSON is always written.
+const (
+	MetricsOutputCSV         = "csv"
+	MetricsOutputParquet     = "parquet"
+	MetricsOutputRemoteWrite = "remote-write"
+)
+
+// MetricsOutputs are the additional metrics output formats.
+var MetricsOutputs = []string{MetricsOutputCSV, MetricsOutputParquet, MetricsOutputRemoteWrite}
+
+const (
+	MetricsCSVFileName     = "metrics.csv"
+	MetricsParquetFileName = "metrics.parquet"
+)
+
+// blockNumberColumn and timestampColumn are the columns written before the
+// metrics in tabular outputs.
+const (
+	blockNumberColumn = "block_number"
+	timestampColumn   = "timestamp"
+)
+
+// metricNames returns the sorted names of the numeric metrics recorded for
+// any block.
+func metricNames(metrics []BlockMetrics) []string {
+	seen := make(map[string]bool)
+	for _, m := range metrics {
+		for name := range m.ExecutionMetrics {
+			if _, ok := m.GetMetricFloat(name); ok {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// blockTime returns the time a block finished processing, falling back to
+// the time its metrics were created.
+func blockTime(m *BlockMetrics) time.Time {
+	if !m.EndTime.IsZero() {
+		return m.EndTime
+	}
+	return m.Timestamp
+}
+
+// CSVMetricsWriter writes one row per block and one column per metric to
+// metrics.csv. Metrics missing from a block are left empty.
+type CSVMetricsWriter struct {
+	BaseDir string
+}
+
+func NewCSVMetricsWriter(baseDir string) *CSVMetricsWriter {
+	return &CSVMetricsWriter{
+		BaseDir: baseDir,
+	}
+}
+
+func (w *CSVMetricsWriter) Write(metrics []BlockMetrics) error {
+	f, err := os.Create(path.Join(w.BaseDir, MetricsCSVFileName))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics csv: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	names := metricNames(metrics)
+	writer := csv.NewWriter(f)
+	if err := writer.Write(append([]string{blockNumberColumn, timestampColumn}, names...)); err != nil {
+		return fmt.Errorf("failed to write metrics csv: %w", err)
+	}
+
+	record := make([]string, len(names)+2)
+	for i := range metrics {
+		m := &metrics[i]
+		record[0] = strconv.FormatUint(m.BlockNumber, 10)
+		record[1] = blockTime(m).UTC().Format(time.RFC3339Nano)
+		for j, name := range names {
+			record[j+2] = ""
+			if value, ok := m.GetMetricFloat(name); ok {
+				record[j+2] = strconv.FormatFloat(value, 'g', -1, 64)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write metrics csv: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write metrics csv: %w", err)
+	}
+	return f.Close()
+}
+
+// ParquetMetricsWriter writes one row per block and one optional double
+// column per metric to metrics.parquet.
+type ParquetMetricsWriter struct {
+	BaseDir string
+}
+
+func NewParquetMetricsWriter(baseDir string) *ParquetMetricsWriter {
+	return &ParquetMetricsWriter{
+		BaseDir: baseDir,
+	}
+}
+
+func (w *ParquetMetricsWriter) Write(metrics []BlockMetrics) error {
+	names := metricNames(metrics)
+	group := parquet.Group{
+		blockNumberColumn: parquet.Uint(64),
+		timestampColumn:   parquet.Timestamp(parquet.Nanosecond),
+	}
+	for _, name := range names {
+		if _, ok := group[name]; ok {
+			return fmt.Errorf("metric %s conflicts with a parquet column", name)
+		}
+		group[name] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	}
+	schema := parquet.NewSchema("metrics", group)
+
+	columnIndex := func(name string) int {
+		leaf, _ := schema.Lookup(name)
+		return leaf.ColumnIndex
+	}
+	blockNumberIndex := columnIndex(blockNumberColumn)
+	timestampIndex := columnIndex(timestampColumn)
+	metricIndexes := make([]int, len(names))
+	for i, name := range names {
+		metricIndexes[i] = columnIndex(name)
+	}
+
+	rows := make([]parquet.Row, len(metrics))
+	for i := range metrics {
+		m := &metrics[i]
+		row := make(parquet.Row, len(names)+2)
+		row[blockNumberIndex] = parquet.Int64Value(int64(m.BlockNumber)).Level(0, 0, blockNumberIndex)
+		row[timestampIndex] = parquet.Int64Value(blockTime(m).UnixNano()).Level(0, 0, timestampIndex)
+		for j, name := range names {
+			index := metricIndexes[j]
+			if value, ok := m.GetMetricFloat(name); ok {
+				row[index] = parquet.DoubleValue(value).Level(0, 1, index)
+			} else {
+				row[index] = parquet.NullValue().Level(0, 0, index)
+			}
+		}
+		rows[i] = row
+	}
+
+	f, err := os.Create(path.Join(w.BaseDir, MetricsParquetFileName))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics parquet: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	writer := parquet.NewWriter(f, schema)
+	if _, err := writer.WriteRows(rows); err != nil {
+		return fmt.Errorf("failed to write metrics parquet: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics parquet: %w", err)
+	}
+	return f.Close()
+}
+
+// MultiMetricsWriter writes metrics to several writers.
+type MultiMetricsWriter []MetricsWriter
+
+func NewMultiMetricsWriter(writers ...MetricsWriter) MultiMetricsWriter {
+	return writers
+}
+
+// Write writes to every writer, even if an earlier one fails.
+func (w MultiMetricsWriter) Write(metrics []BlockMetrics) error {
+	var errs []error
+	for _, writer := range w {
+		if err := writer.Write(metrics); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
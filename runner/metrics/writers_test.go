@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlockMetrics() []BlockMetrics {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := NewBlockMetrics()
+	first.SetBlockNumber(1)
+	first.EndTime = start
+	first.AddExecutionMetric("latency/new_payload", 2*time.Millisecond)
+	first.AddExecutionMetric("gas/per_second", 1.5e9)
+
+	second := NewBlockMetrics()
+	second.SetBlockNumber(2)
+	second.EndTime = start.Add(time.Second)
+	second.AddExecutionMetric("latency/new_payload", 3*time.Millisecond)
+	second.AddExecutionMetric("reth_db_table_size{table=Headers}", uint64(4096))
+	// non-numeric values are not written
+	second.AddExecutionMetric("client/version", "reth/v1.0.0")
+
+	return []BlockMetrics{*first, *second}
+}
+
+func TestCSVMetricsWriter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewCSVMetricsWriter(dir).Write(testBlockMetrics()))
+
+	f, err := os.Open(path.Join(dir, MetricsCSVFileName))
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"block_number", "timestamp", "gas/per_second", "latency/new_payload", "reth_db_table_size{table=Headers}"},
+		{"1", "2025-01-01T00:00:00Z", "1.5e+09", "0.002", ""},
+		{"2", "2025-01-01T00:00:01Z", "", "0.003", "4096"},
+	}, records)
+}
+
+func TestParquetMetricsWriter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewParquetMetricsWriter(dir).Write(testBlockMetrics()))
+
+	type row struct {
+		BlockNumber uint64    `parquet:"block_number"`
+		Timestamp   time.Time `parquet:"timestamp,timestamp(nanosecond)"`
+		GasPerSec   *float64  `parquet:"gas/per_second,optional"`
+		NewPayload  *float64  `parquet:"latency/new_payload,optional"`
+		TableSize   *float64  `parquet:"reth_db_table_size{table=Headers},optional"`
+	}
+	rows, err := parquet.ReadFile[row](path.Join(dir, MetricsParquetFileName))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.Equal(t, uint64(1), rows[0].BlockNumber)
+	require.True(t, rows[0].Timestamp.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, 1.5e9, *rows[0].GasPerSec)
+	require.Equal(t, 0.002, *rows[0].NewPayload)
+	require.Nil(t, rows[0].TableSize)
+
+	require.Equal(t, uint64(2), rows[1].BlockNumber)
+	require.Nil(t, rows[1].GasPerSec)
+	require.Equal(t, 0.003, *rows[1].NewPayload)
+	require.Equal(t, 4096.0, *rows[1].TableSize)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]BlockMetrics) error {
+	return errors.New("failed")
+}
+
+func TestMultiMetricsWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewMultiMetricsWriter(failingWriter{}, NewCSVMetricsWriter(dir))
+
+	// later writers still run if an earlier one fails
+	require.Error(t, writer.Write(testBlockMetrics()))
+	require.FileExists(t, path.Join(dir, MetricsCSVFileName))
+}
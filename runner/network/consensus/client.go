@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/base/base-bench/runner/tracing"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum/go-ethereum/beacon/engine"
@@ -11,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ConsensusClientOptions is an object for configuring a ConsensusClient.
@@ -56,44 +59,92 @@ func (f *BaseConsensusClient) updateForkChoice(ctx context.Context, payloadAttrs
 		FinalizedBlockHash: f.headBlockHash,
 	}
 
+	ctx, span := tracing.Tracer().Start(ctx, "engine_forkchoiceUpdatedV3", trace.WithAttributes(
+		attribute.String("head_block_hash", f.headBlockHash.Hex()),
+		attribute.Bool("payload_attributes", payloadAttrs != nil),
+	))
+	if payloadAttrs != nil {
+		span.SetAttributes(
+			attribute.Int64("attributes.timestamp", int64(payloadAttrs.Timestamp)),
+			attribute.Int("attributes.tx_count", len(payloadAttrs.Transactions)),
+		)
+		if payloadAttrs.GasLimit != nil {
+			span.SetAttributes(attribute.Int64("attributes.gas_limit", int64(*payloadAttrs.GasLimit)))
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var resp engine.ForkChoiceResponse
 	err := f.authClient.CallContext(ctx, &resp, "engine_forkchoiceUpdatedV3", fcu, payloadAttrs)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to propose block")
+		err = errors.Wrap(err, "failed to propose block")
+		tracing.End(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("status", resp.PayloadStatus.Status))
+	if resp.PayloadID != nil {
+		span.SetAttributes(attribute.String("payload_id", resp.PayloadID.String()))
 	}
+	span.End()
 
 	return resp.PayloadID, nil
 }
 
 // getBuiltPayload retrieves the built payload for the given payload ID.
 func (b *BaseConsensusClient) getBuiltPayload(ctx context.Context, payloadID engine.PayloadID) (*engine.ExecutableData, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "engine_getPayloadV4", trace.WithAttributes(
+		attribute.String("payload_id", payloadID.String()),
+	))
+
 	ctx, cancel := context.WithTimeout(ctx, 240*time.Second)
 	defer cancel()
 	var payloadResp engine.ExecutionPayloadEnvelope
 	err := b.authClient.CallContext(ctx, &payloadResp, "engine_getPayloadV4", payloadID)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get payload")
+		err = errors.Wrap(err, "failed to get payload")
+		tracing.End(span, err)
+		return nil, err
 	}
 
 	b.log.Debug("Built payload", "parent_hash", payloadResp.ExecutionPayload.ParentHash, "stateRoot", payloadResp.ExecutionPayload.StateRoot)
 
+	span.SetAttributes(payloadAttributes(payloadResp.ExecutionPayload)...)
+	span.End()
+
 	return payloadResp.ExecutionPayload, nil
 }
 
-// newPayload calls engine_newPayloadV4 with the given executable data.
-func (b *BaseConsensusClient) newPayload(ctx context.Context, params *engine.ExecutableData, beaconRoot common.Hash) error {
+// newPayload calls engine_newPayloadV4 with the given executable data and
+// returns the payload status reported by the client.
+func (b *BaseConsensusClient) newPayload(ctx context.Context, params *engine.ExecutableData, beaconRoot common.Hash) (*engine.PayloadStatusV1, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "engine_newPayloadV4", trace.WithAttributes(payloadAttributes(params)...))
 
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	var resp engine.ForkChoiceResponse
+	var resp engine.PayloadStatusV1
 	err := b.authClient.CallContext(ctx, &resp, "engine_newPayloadV4", params, []common.Hash{}, beaconRoot, []common.Hash{})
 
 	if err != nil {
-		return errors.Wrap(err, "newPayload call failed")
+		err = errors.Wrap(err, "newPayload call failed")
+		tracing.End(span, err)
+		return nil, err
 	}
 
-	return nil
+	span.SetAttributes(attribute.String("status", resp.Status))
+	span.End()
+
+	return &resp, nil
+}
+
+// payloadAttributes returns the span attributes describing a payload.
+func payloadAttributes(payload *engine.ExecutableData) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("block_number", int64(payload.Number)),
+		attribute.String("block_hash", payload.BlockHash.Hex()),
+		attribute.Int64("gas_used", int64(payload.GasUsed)),
+		attribute.Int("tx_count", len(payload.Transactions)),
+	}
 }
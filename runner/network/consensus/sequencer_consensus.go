@@ -12,6 +12,7 @@ import (
 	"github.com/base/base-bench/runner/network/mempool"
 	"github.com/base/base-bench/runner/network/proofprogram/fakel1"
 	networktypes "github.com/base/base-bench/runner/network/types"
+	"github.com/base/base-bench/runner/tracing"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
@@ -26,15 +27,24 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SequencerConsensusClient is a fake consensus client that generates blocks on a timer.
 type SequencerConsensusClient struct {
 	*BaseConsensusClient
 	lastTimestamp uint64
-	mempool       mempool.FakeMempool
-	l1Chain       fakel1.L1Chain
-	batcherAddr   common.Address
+	// lastSealTime is when the last payload was due to be fetched. Blocks are
+	// sealed every BlockTime, so the work between sealing a block and starting
+	// the next one counts towards the next block's time.
+	lastSealTime time.Time
+	mempool      mempool.FakeMempool
+	l1Chain      fakel1.L1Chain
+	batcherAddr  common.Address
+
+	// payloadAttributes are the attributes the last payload was built from.
+	payloadAttributes *eth.PayloadAttributes
 }
 
 // NewSequencerConsensusClient creates a new consensus client using the given genesis hash and timestamp.
@@ -49,6 +59,12 @@ func NewSequencerConsensusClient(log log.Logger, client *ethclient.Client, authC
 	}
 }
 
+// PayloadAttributes returns the attributes the last proposed payload was built
+// from.
+func (f *SequencerConsensusClient) PayloadAttributes() *eth.PayloadAttributes {
+	return f.payloadAttributes
+}
+
 func (f *SequencerConsensusClient) Stop(ctx context.Context) error {
 	f.log.Info("Stopping sequencer consensus client")
 
@@ -117,7 +133,7 @@ func (f *SequencerConsensusClient) generatePayloadAttributes(sequencerTxs [][]by
 	var b8 eth.Bytes8
 	copy(b8[:], eip1559.EncodeHolocene1559Params(50, 1))
 
-	timestamp := f.lastTimestamp + 1
+	timestamp := f.lastTimestamp + networktypes.TimestampIncrement(f.options.BlockTime)
 
 	number := uint64(0)
 	time := uint64(0)
@@ -199,39 +215,119 @@ func (f *SequencerConsensusClient) generatePayloadAttributes(sequencerTxs [][]by
 	return payloadAttrs, &root, nil
 }
 
-// Propose starts block generation, waits BlockTime, and generates a block.
-func (f *SequencerConsensusClient) Propose(ctx context.Context, blockMetrics *metrics.BlockMetrics, isSetupPayload bool) (*engine.ExecutableData, error) {
-	startTime := time.Now()
-
-	sendTxs, sequencerTxs := f.mempool.NextBlock()
-
+// sendTxs sends the transactions of the next block to the client in batches.
+func (f *SequencerConsensusClient) sendTxs(ctx context.Context, txs [][]byte) (err error) {
 	sendCallsPerBatch := 100
-	batches := (len(sendTxs) + sendCallsPerBatch - 1) / sendCallsPerBatch
+	batches := (len(txs) + sendCallsPerBatch - 1) / sendCallsPerBatch
+
+	ctx, span := tracing.Tracer().Start(ctx, "send_txs", trace.WithAttributes(
+		attribute.Int("tx_count", len(txs)),
+		attribute.Int("batches", batches),
+	))
+	defer func() {
+		tracing.End(span, err)
+	}()
 
 	for i := 0; i < batches; i++ {
-		batch := sendTxs[i*sendCallsPerBatch : min((i+1)*sendCallsPerBatch, len(sendTxs))]
-		results := make([]interface{}, len(batch))
-
-		batchCall := make([]rpc.BatchElem, len(batch))
-		for j, tx := range batch {
-			batchCall[j] = rpc.BatchElem{
-				Method: "eth_sendRawTransaction",
-				Args:   []interface{}{hexutil.Encode(tx)},
-				Result: &results[j],
-			}
+		batch := txs[i*sendCallsPerBatch : min((i+1)*sendCallsPerBatch, len(txs))]
+		if err := f.sendTxsBatch(ctx, i, batch); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		err := f.client.Client().BatchCallContext(ctx, batchCall)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to send transactions")
+// sendTxsBatch sends a batch of transactions in a single batch RPC call.
+func (f *SequencerConsensusClient) sendTxsBatch(ctx context.Context, index int, batch [][]byte) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "send_txs_batch", trace.WithAttributes(
+		attribute.Int("batch", index),
+		attribute.Int("tx_count", len(batch)),
+	))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	results := make([]interface{}, len(batch))
+
+	batchCall := make([]rpc.BatchElem, len(batch))
+	for j, tx := range batch {
+		batchCall[j] = rpc.BatchElem{
+			Method: "eth_sendRawTransaction",
+			Args:   []interface{}{hexutil.Encode(tx)},
+			Result: &results[j],
 		}
+	}
+
+	err = f.client.Client().BatchCallContext(ctx, batchCall)
+	if err != nil {
+		return errors.Wrap(err, "failed to send transactions")
+	}
+
+	for _, tx := range batchCall {
+		if tx.Error != nil {
+			return errors.Wrapf(tx.Error, "failed to send transaction %#v", tx.Args[0])
+		}
+	}
+	return nil
+}
+
+// waitForSealTime waits until the block being built is due, BlockTime after
+// the previous block was sealed. Blocks that overran the block time are
+// sealed immediately and the cadence restarts from them.
+func (f *SequencerConsensusClient) waitForSealTime(ctx context.Context) error {
+	now := time.Now()
+	sealTime := now.Add(f.options.BlockTime)
+	if !f.lastSealTime.IsZero() {
+		sealTime = f.lastSealTime.Add(f.options.BlockTime)
+	}
+	if sealTime.Before(now) {
+		f.log.Debug("Block overran the block time", "overrun", now.Sub(sealTime))
+		sealTime = now
+	}
+	f.lastSealTime = sealTime
+
+	_, waitSpan := tracing.Tracer().Start(ctx, "block_time_wait", trace.WithAttributes(
+		attribute.Int64("block_time_ms", f.options.BlockTime.Milliseconds()),
+		attribute.Int64("wait_ms", sealTime.Sub(now).Milliseconds()),
+	))
+	defer waitSpan.End()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(sealTime)):
+		return nil
+	}
+}
 
-		for _, tx := range batchCall {
-			if tx.Error != nil {
-				return nil, errors.Wrapf(tx.Error, "failed to send transaction %#v", tx.Args[0])
-			}
+// Propose starts block generation, waits until the block is due, and
+// generates a block. Blocks are due every BlockTime.
+func (f *SequencerConsensusClient) Propose(ctx context.Context, blockMetrics *metrics.BlockMetrics, isSetupPayload bool) (*engine.ExecutableData, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "block", trace.WithAttributes(
+		attribute.Int64("block_index", int64(blockMetrics.BlockNumber)),
+		attribute.Bool("setup", isSetupPayload),
+	))
+
+	payload, err := f.propose(ctx, blockMetrics, isSetupPayload)
+	if err == nil {
+		span.SetAttributes(payloadAttributes(payload)...)
+		if f.currentPayloadID != nil {
+			span.SetAttributes(attribute.String("payload_id", f.currentPayloadID.String()))
 		}
 	}
+	tracing.End(span, err)
+	return payload, err
+}
+
+func (f *SequencerConsensusClient) propose(ctx context.Context, blockMetrics *metrics.BlockMetrics, isSetupPayload bool) (*engine.ExecutableData, error) {
+	startTime := time.Now()
+
+	sendTxs, sequencerTxs := f.mempool.NextBlock()
+	blockMetrics.AddExecutionMetric(networktypes.TransactionsSentMetric, len(sendTxs))
+
+	if err := f.sendTxs(ctx, sendTxs); err != nil {
+		return nil, err
+	}
 
 	duration := time.Since(startTime)
 	f.log.Info("Sent transactions", "duration", duration, "num_txs", len(sendTxs))
@@ -258,8 +354,11 @@ func (f *SequencerConsensusClient) Propose(ctx context.Context, blockMetrics *me
 	blockMetrics.AddExecutionMetric(networktypes.UpdateForkChoiceLatencyMetric, duration)
 
 	f.currentPayloadID = payloadID
-	// wait block time
-	time.Sleep(f.options.BlockTime)
+	f.payloadAttributes = payloadAttrs
+
+	if err := f.waitForSealTime(ctx); err != nil {
+		return nil, err
+	}
 
 	startTime = time.Now()
 
@@ -286,7 +385,7 @@ func (f *SequencerConsensusClient) Propose(ctx context.Context, blockMetrics *me
 	transactionsPerBlock := len(payload.Transactions)
 	blockMetrics.AddExecutionMetric(networktypes.TransactionsPerBlockMetric, transactionsPerBlock)
 
-	err = f.newPayload(ctx, payload, *beaconRoot)
+	_, err = f.newPayload(ctx, payload, *beaconRoot)
 	if err != nil {
 		return nil, err
 	}
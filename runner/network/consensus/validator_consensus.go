@@ -7,17 +7,23 @@ import (
 
 	"github.com/base/base-bench/runner/metrics"
 	"github.com/base/base-bench/runner/network/types"
+	"github.com/base/base-bench/runner/tracing"
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SyncingConsensusClient is a fake consensus client that generates blocks on a timer.
 type SyncingConsensusClient struct {
 	*BaseConsensusClient
+
+	// payloadStatuses holds the status of every payload the client did not
+	// accept as valid, keyed by block number.
+	payloadStatuses map[uint64]engine.PayloadStatusV1
 }
 
 // NewSyncingConsensusClient creates a new consensus client.
@@ -25,9 +31,16 @@ func NewSyncingConsensusClient(log log.Logger, client *ethclient.Client, authCli
 	base := NewBaseConsensusClient(log, client, authClient, options, headBlockHash, headBlockNumber)
 	return &SyncingConsensusClient{
 		BaseConsensusClient: base,
+		payloadStatuses:     make(map[uint64]engine.PayloadStatusV1),
 	}
 }
 
+// PayloadStatuses returns the status of every payload that was not accepted
+// as valid, keyed by block number.
+func (f *SyncingConsensusClient) PayloadStatuses() map[uint64]engine.PayloadStatusV1 {
+	return f.payloadStatuses
+}
+
 // Propose starts block generation, waits BlockTime, and generates a block.
 func (f *SyncingConsensusClient) propose(ctx context.Context, payload *engine.ExecutableData, blockMetrics *metrics.BlockMetrics) error {
 
@@ -35,10 +48,18 @@ func (f *SyncingConsensusClient) propose(ctx context.Context, payload *engine.Ex
 
 	f.log.Info("Validate payload", "payload_index", payload.Number)
 	startTime := time.Now()
-	err := f.newPayload(ctx, payload, root)
+	status, err := f.newPayload(ctx, payload, root)
 	if err != nil {
 		return err
 	}
+	if status.Status != engine.VALID {
+		validationError := ""
+		if status.ValidationError != nil {
+			validationError = *status.ValidationError
+		}
+		f.log.Warn("Payload not accepted", "number", payload.Number, "status", status.Status, "validationError", validationError)
+		f.payloadStatuses[payload.Number] = *status
+	}
 
 	f.headBlockHash = payload.BlockHash
 	duration := time.Since(startTime)
@@ -70,10 +91,14 @@ func (f *SyncingConsensusClient) Start(ctx context.Context, payloads []engine.Ex
 	for i := 0; i < len(payloads); i++ {
 		m.SetBlockNumber(uint64(max(0, int(payloads[i].Number)-int(firstTestBlock))))
 		f.log.Info("Proposing payload", "payload_index", i)
-		err := f.propose(ctx, &payloads[i], m)
+		m.StartTime = time.Now()
+		blockCtx, span := tracing.Tracer().Start(ctx, "block", trace.WithAttributes(payloadAttributes(&payloads[i])...))
+		err := f.propose(blockCtx, &payloads[i], m)
+		tracing.End(span, err)
 		if err != nil {
 			return err
 		}
+		m.EndTime = time.Now()
 
 		if payloads[i].Number >= firstTestBlock {
 			err = metricsCollector.Collect(ctx, m)
@@ -0,0 +1,160 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/clients/types"
+	benchtypes "github.com/base/base-bench/runner/network/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// traceConfig records the state diff of every transaction, which pinpoints
+// the accounts and slots that differ without dumping every opcode.
+var traceConfig = map[string]interface{}{
+	"tracer": "prestateTracer",
+	"tracerConfig": map[string]interface{}{
+		"diffMode": true,
+	},
+}
+
+func headerFields(blockHash string, stateRoot string, receiptsRoot string, logsBloom string) map[string]string {
+	return map[string]string{
+		"blockHash":    blockHash,
+		"stateRoot":    stateRoot,
+		"receiptsRoot": receiptsRoot,
+		"logsBloom":    logsBloom,
+	}
+}
+
+// compareBlock compares the validator's header for the payload's block number
+// against the payload built by the sequencer. It returns nil if they match.
+func compareBlock(ctx context.Context, validatorClient types.ExecutionClient, payload *engine.ExecutableData, payloadStatuses map[uint64]engine.PayloadStatusV1) (*benchmark.BlockDivergence, error) {
+	header, err := validatorClient.Client().HeaderByNumber(ctx, new(big.Int).SetUint64(payload.Number))
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, errors.Wrapf(err, "failed to get validator header %d", payload.Number)
+	}
+
+	divergence := &benchmark.BlockDivergence{
+		BlockNumber: payload.Number,
+		Sequencer:   headerFields(payload.BlockHash.Hex(), payload.StateRoot.Hex(), payload.ReceiptsRoot.Hex(), hexutil.Encode(payload.LogsBloom)),
+	}
+	if status, ok := payloadStatuses[payload.Number]; ok && status.ValidationError != nil {
+		divergence.ValidationError = *status.ValidationError
+	}
+
+	// the validator rejected the block, so there is no header to compare
+	if header == nil {
+		return divergence, nil
+	}
+
+	divergence.Validator = headerFields(header.Hash().Hex(), header.Root.Hex(), header.ReceiptHash.Hex(), hexutil.Encode(header.Bloom.Bytes()))
+	if header.Root != payload.StateRoot {
+		divergence.Fields = append(divergence.Fields, "stateRoot")
+	}
+	if header.ReceiptHash != payload.ReceiptsRoot {
+		divergence.Fields = append(divergence.Fields, "receiptsRoot")
+	}
+	if header.Bloom != ethtypes.BytesToBloom(payload.LogsBloom) {
+		divergence.Fields = append(divergence.Fields, "logsBloom")
+	}
+	if header.Hash() != payload.BlockHash {
+		divergence.Fields = append(divergence.Fields, "blockHash")
+	}
+
+	if len(divergence.Fields) == 0 {
+		return nil, nil
+	}
+	return divergence, nil
+}
+
+// checkConsistency checks every test block imported by the validator against
+// the payload built by the sequencer and dumps traces of the first divergent
+// block from both nodes.
+func (nb *NetworkBenchmark) checkConsistency(ctx context.Context, validatorClient types.ExecutionClient, payloads []engine.ExecutableData, firstTestBlock uint64, payloadStatuses map[uint64]engine.PayloadStatusV1) (*benchmark.ConsistencyResult, error) {
+	result := &benchmark.ConsistencyResult{}
+
+	for i := range payloads {
+		payload := &payloads[i]
+		if payload.Number < firstTestBlock {
+			continue
+		}
+		result.BlocksChecked++
+
+		divergence, err := compareBlock(ctx, validatorClient, payload, payloadStatuses)
+		if err != nil {
+			return nil, err
+		}
+		if divergence == nil {
+			continue
+		}
+
+		nb.log.Error("Validator diverged from sequencer", "number", payload.Number, "fields", divergence.Fields, "validationError", divergence.ValidationError)
+		divergence.TraceFiles = nb.dumpTraces(ctx, validatorClient, payload.Number)
+		result.Divergence = divergence
+		break
+	}
+
+	if result.Divergence == nil {
+		nb.log.Info("Validator matches sequencer", "blocksChecked", result.BlocksChecked)
+	}
+	return result, nil
+}
+
+// dumpTraces writes debug_traceBlock of the given block from both nodes to the
+// output directory and returns the written file names keyed by role. Failures
+// are logged, as the traces are only a debugging aid.
+func (nb *NetworkBenchmark) dumpTraces(ctx context.Context, validatorClient types.ExecutionClient, blockNumber uint64) map[string]string {
+	// replayed payloads have no sequencer to trace the block on
+	if nb.sequencerOptions == nil {
+		return nil
+	}
+
+	// the sequencer is stopped after building blocks, so restart it from its
+	// datadir to trace the block it built
+	sequencerClient, err := setupNode(ctx, nb.log, nb.testConfig.Params, benchtypes.SequencerRole, nb.sequencerOptions, nb.ports)
+	if err != nil {
+		nb.log.Error("Failed to restart sequencer for tracing", "err", err)
+		return nil
+	}
+	defer sequencerClient.Stop()
+
+	var rawBlock hexutil.Bytes
+	err = sequencerClient.Client().Client().CallContext(ctx, &rawBlock, "debug_getRawBlock", hexutil.EncodeUint64(blockNumber))
+	if err != nil {
+		nb.log.Error("Failed to get raw block from sequencer", "number", blockNumber, "err", err)
+		return nil
+	}
+
+	clients := map[string]types.ExecutionClient{
+		benchtypes.SequencerRole: sequencerClient,
+		benchtypes.ValidatorRole: validatorClient,
+	}
+
+	traceFiles := make(map[string]string)
+	for role, client := range clients {
+		var trace json.RawMessage
+		err := client.Client().Client().CallContext(ctx, &trace, "debug_traceBlock", rawBlock, traceConfig)
+		if err != nil {
+			nb.log.Error("Failed to trace block", "role", role, "number", blockNumber, "err", err)
+			continue
+		}
+
+		fileName := fmt.Sprintf("trace-%s-%d.json", role, blockNumber)
+		if err := os.WriteFile(path.Join(nb.outputDir, fileName), trace, 0644); err != nil {
+			nb.log.Error("Failed to write trace", "role", role, "err", err)
+			continue
+		}
+		traceFiles[role] = fileName
+	}
+	return traceFiles
+}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"context"
+
+	"github.com/base/base-bench/runner/metrics"
+	benchtypes "github.com/base/base-bench/runner/network/types"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const liveMetricsNamespace = "base_bench"
+
+// liveLatencyMetrics maps the latency block metrics to the call label of the
+// latency histogram.
+var liveLatencyMetrics = map[string]string{
+	benchtypes.UpdateForkChoiceLatencyMetric: "forkchoice_updated",
+	benchtypes.GetPayloadLatencyMetric:       "get_payload",
+	benchtypes.NewPayloadLatencyMetric:       "new_payload",
+	benchtypes.SendTxsLatencyMetric:          "send_txs",
+}
+
+// LiveMetrics exposes the progress of a benchmark suite as Prometheus metrics
+// while it runs. Block values are fed from the same block metrics that are
+// written to metrics.json.
+type LiveMetrics struct {
+	registry *prometheus.Registry
+
+	runInfo          *prometheus.GaugeVec
+	blockNumber      *prometheus.GaugeVec
+	gasPerSecond     *prometheus.GaugeVec
+	transactionsSent *prometheus.GaugeVec
+	latency          *prometheus.HistogramVec
+	runs             *prometheus.CounterVec
+}
+
+func NewLiveMetrics() *LiveMetrics {
+	registry := opmetrics.NewRegistry()
+	factory := opmetrics.With(registry)
+
+	return &LiveMetrics{
+		registry: registry,
+		runInfo: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "run_info",
+			Help:      "Set to 1 for each run in progress",
+		}, []string{"benchmark_run", "run", "test", "node_type"}),
+		blockNumber: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "block_number",
+			Help:      "Last benchmark block processed by each client of a run in progress",
+		}, []string{"run", "role"}),
+		gasPerSecond: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "gas_per_second",
+			Help:      "Gas per second of the last block processed by each client of a run in progress",
+		}, []string{"run", "role"}),
+		transactionsSent: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "transactions_sent",
+			Help:      "Transactions sent to the sequencer for its last block",
+		}, []string{"run"}),
+		latency: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "latency_seconds",
+			Help:      "Latency of the Engine API calls and transaction submission of each block",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+		}, []string{"role", "node_type", "call"}),
+		runs: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: liveMetricsNamespace,
+			Name:      "runs_total",
+			Help:      "Number of runs finished, by result",
+		}, []string{"result"}),
+	}
+}
+
+// Registry returns the registry the metrics are served from.
+func (l *LiveMetrics) Registry() *prometheus.Registry {
+	return l.registry
+}
+
+// StartRun marks a run as in progress. The returned LiveRun records the
+// metrics of its blocks until Finish is called. The run ID must be unique
+// among the runs in progress.
+func (l *LiveMetrics) StartRun(params benchtypes.RunParams, runID string) *LiveRun {
+	l.runInfo.WithLabelValues(params.BenchmarkRunID, runID, params.Name, params.NodeTypeLabel()).Set(1)
+	return &LiveRun{
+		metrics: l,
+		params:  params,
+		runID:   runID,
+	}
+}
+
+// LiveRun records the live metrics of a single run.
+type LiveRun struct {
+	metrics *LiveMetrics
+	params  benchtypes.RunParams
+	runID   string
+}
+
+// Finish counts the run's result and removes its per-run metrics.
+func (r *LiveRun) Finish(success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	r.metrics.runs.WithLabelValues(result).Inc()
+
+	labels := prometheus.Labels{"run": r.runID}
+	r.metrics.runInfo.DeletePartialMatch(labels)
+	r.metrics.blockNumber.DeletePartialMatch(labels)
+	r.metrics.gasPerSecond.DeletePartialMatch(labels)
+	r.metrics.transactionsSent.DeletePartialMatch(labels)
+}
+
+// Collector wraps a client's metrics collector so that every collected block
+// updates the live metrics.
+func (r *LiveRun) Collector(role string, collector metrics.Collector) metrics.Collector {
+	return &liveCollector{
+		Collector: collector,
+		run:       r,
+		role:      role,
+	}
+}
+
+func (r *LiveRun) observe(role string, m *metrics.BlockMetrics) {
+	r.metrics.blockNumber.WithLabelValues(r.runID, role).Set(float64(m.BlockNumber))
+	if value, ok := m.GetMetricFloat(benchtypes.GasPerSecondMetric); ok {
+		r.metrics.gasPerSecond.WithLabelValues(r.runID, role).Set(value)
+	}
+	if value, ok := m.GetMetricFloat(benchtypes.TransactionsSentMetric); ok {
+		r.metrics.transactionsSent.WithLabelValues(r.runID).Set(value)
+	}
+
+	nodeType := r.params.NodeTypeFor(role)
+	for metric, call := range liveLatencyMetrics {
+		if value, ok := m.GetMetricFloat(metric); ok {
+			r.metrics.latency.WithLabelValues(role, nodeType, call).Observe(value)
+		}
+	}
+}
+
+type liveCollector struct {
+	metrics.Collector
+	run  *LiveRun
+	role string
+}
+
+func (c *liveCollector) Collect(ctx context.Context, m *metrics.BlockMetrics) error {
+	c.run.observe(c.role, m)
+	return c.Collector.Collect(ctx, m)
+}
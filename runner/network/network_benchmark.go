@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
 
 	"github.com/base/base-bench/runner/benchmark"
 	"github.com/base/base-bench/runner/benchmark/portmanager"
 	"github.com/base/base-bench/runner/clients"
+	"github.com/base/base-bench/runner/clients/common"
 	"github.com/base/base-bench/runner/clients/types"
 	"github.com/base/base-bench/runner/config"
 	"github.com/base/base-bench/runner/payload"
@@ -16,10 +18,14 @@ import (
 	"github.com/base/base-bench/runner/logger"
 	"github.com/base/base-bench/runner/metrics"
 
+	"github.com/base/base-bench/runner/network/recording"
 	benchtypes "github.com/base/base-bench/runner/network/types"
+	"github.com/base/base-bench/runner/tracing"
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -36,15 +42,33 @@ type NetworkBenchmark struct {
 	collectedSequencerMetrics *benchtypes.SequencerKeyMetrics
 	collectedValidatorMetrics *benchtypes.ValidatorKeyMetrics
 
+	sequencerBlockMetrics []metrics.BlockMetrics
+	validatorBlockMetrics []metrics.BlockMetrics
+
 	testConfig  *benchtypes.TestConfig
 	proofConfig *benchmark.ProofProgramOptions
 
 	transactionPayload payload.Definition
 	ports              portmanager.PortManager
+
+	// verifyConsistency enables checking the validator's blocks against the
+	// sequencer's payloads. Traces of divergent blocks go to outputDir.
+	verifyConsistency bool
+	outputDir         string
+	consistency       *benchmark.ConsistencyResult
+
+	// clientVersions are the versions of the clients run, keyed by role.
+	clientVersions map[string]*types.ClientVersion
+
+	// live exposes the metrics of each block while the benchmark runs.
+	live *LiveRun
+
+	// recording holds the payloads built by the sequencer.
+	recording *recording.Recording
 }
 
 // NewNetworkBenchmark creates a new network benchmark and initializes the payload worker and consensus client
-func NewNetworkBenchmark(config *benchtypes.TestConfig, log log.Logger, sequencerOptions *config.InternalClientOptions, validatorOptions *config.InternalClientOptions, proofConfig *benchmark.ProofProgramOptions, transactionPayload payload.Definition, ports portmanager.PortManager) (*NetworkBenchmark, error) {
+func NewNetworkBenchmark(config *benchtypes.TestConfig, log log.Logger, sequencerOptions *config.InternalClientOptions, validatorOptions *config.InternalClientOptions, proofConfig *benchmark.ProofProgramOptions, transactionPayload payload.Definition, ports portmanager.PortManager, verifyConsistency bool, outputDir string, live *LiveRun) (*NetworkBenchmark, error) {
 	return &NetworkBenchmark{
 		log:                log,
 		sequencerOptions:   sequencerOptions,
@@ -53,15 +77,23 @@ func NewNetworkBenchmark(config *benchtypes.TestConfig, log log.Logger, sequence
 		proofConfig:        proofConfig,
 		transactionPayload: transactionPayload,
 		ports:              ports,
+		verifyConsistency:  verifyConsistency,
+		outputDir:          outputDir,
+		clientVersions:     make(map[string]*types.ClientVersion),
+		live:               live,
 	}, nil
 }
 
 // Run executes the benchmark test
-func (nb *NetworkBenchmark) Run(ctx context.Context) error {
+func (nb *NetworkBenchmark) Run(ctx context.Context) (err error) {
+	ctx, span := nb.startRunSpan(ctx)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// Create an L1 chain if needed for fault proof benchmark
 	var l1Chain *l1Chain
 	if nb.proofConfig != nil {
-		var err error
 		l1Chain, err = newL1Chain(nb.testConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create L1 chain: %w", err)
@@ -69,24 +101,78 @@ func (nb *NetworkBenchmark) Run(ctx context.Context) error {
 	}
 
 	// Benchmark the sequencer first to build payloads
-	payloads, firstTestBlock, err := nb.benchmarkSequencer(ctx, l1Chain)
+	sequencerCtx, sequencerSpan := nb.startRoleSpan(ctx, benchtypes.SequencerRole)
+	payloads, firstTestBlock, err := nb.benchmarkSequencer(sequencerCtx, l1Chain)
+	tracing.End(sequencerSpan, err)
 	if err != nil {
 		return fmt.Errorf("failed to run sequencer benchmark: %w", err)
 	}
 
 	// Benchmark the validator to sync the payloads
-	if err := nb.benchmarkValidator(ctx, payloads, firstTestBlock, l1Chain); err != nil {
+	validatorCtx, validatorSpan := nb.startRoleSpan(ctx, benchtypes.ValidatorRole)
+	err = nb.benchmarkValidator(validatorCtx, payloads, firstTestBlock, l1Chain)
+	tracing.End(validatorSpan, err)
+	if err != nil {
 		return fmt.Errorf("failed to run validator benchmark: %w", err)
 	}
 
 	return nil
 }
 
+// Replay benchmarks the validator against payloads recorded by a previous run
+// instead of building them on the sequencer first. The sequencer options may
+// be nil.
+func (nb *NetworkBenchmark) Replay(ctx context.Context, payloads []engine.ExecutableData, firstTestBlock uint64) (err error) {
+	ctx, span := nb.startRunSpan(ctx)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	validatorCtx, validatorSpan := nb.startRoleSpan(ctx, benchtypes.ValidatorRole)
+	err = nb.benchmarkValidator(validatorCtx, payloads, firstTestBlock, nil)
+	tracing.End(validatorSpan, err)
+	if err != nil {
+		return fmt.Errorf("failed to run validator benchmark: %w", err)
+	}
+
+	return nil
+}
+
+// startRunSpan starts the root span of a run, so that the block spans of all
+// its clients share a trace.
+func (nb *NetworkBenchmark) startRunSpan(ctx context.Context) (context.Context, trace.Span) {
+	params := nb.testConfig.Params
+	return tracing.Tracer().Start(ctx, "run", trace.WithAttributes(
+		attribute.String("run", nb.testConfig.RunID),
+		attribute.Int("repetition", nb.testConfig.Repetition),
+		attribute.String("test", params.Name),
+		attribute.String("benchmark_run", params.BenchmarkRunID),
+		attribute.String("node_type", params.NodeTypeLabel()),
+		attribute.String("payload", params.PayloadID),
+	))
+}
+
+// startRoleSpan starts the span of a client's benchmark within a run.
+func (nb *NetworkBenchmark) startRoleSpan(ctx context.Context, role string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, role, trace.WithAttributes(
+		attribute.String("run", nb.testConfig.RunID),
+		attribute.String("role", role),
+		attribute.String("node_type", nb.testConfig.Params.NodeTypeFor(role)),
+	))
+}
+
+// Recording returns the payloads built by the sequencer, or nil if it did not
+// finish building them.
+func (nb *NetworkBenchmark) Recording() *recording.Recording {
+	return nb.recording
+}
+
 func (nb *NetworkBenchmark) benchmarkSequencer(ctx context.Context, l1Chain *l1Chain) ([]engine.ExecutableData, uint64, error) {
-	sequencerClient, err := setupNode(ctx, nb.log, nb.testConfig.Params, nb.sequencerOptions, nb.ports)
+	sequencerClient, err := setupNode(ctx, nb.log, nb.testConfig.Params, benchtypes.SequencerRole, nb.sequencerOptions, nb.ports)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to setup sequencer node: %w", err)
 	}
+	nb.recordClientVersion(ctx, benchtypes.SequencerRole, sequencerClient)
 
 	// Ensure client is stopped even if benchmark fails
 	defer func() {
@@ -100,13 +186,19 @@ func (nb *NetworkBenchmark) benchmarkSequencer(ctx context.Context, l1Chain *l1C
 	}()
 
 	// Create metrics collector and writer
-	metricsCollector := sequencerClient.MetricsCollector()
-	metricsWriter := metrics.NewFileMetricsWriter(nb.sequencerOptions.MetricsPath)
+	metricsCollector, stopScraper := nb.startMetricsScraper(ctx, benchtypes.SequencerRole, sequencerClient, nb.sequencerOptions)
+	defer stopScraper()
+	metricsCollector, stopSampler := nb.startResourceSampler(ctx, benchtypes.SequencerRole, sequencerClient, nb.sequencerOptions, metricsCollector)
+	defer stopSampler()
+	metricsCollector = nb.measureDataDir(metricsCollector, nb.sequencerOptions)
+	metricsCollector = nb.live.Collector(benchtypes.SequencerRole, metricsCollector)
+	metricsWriter := nb.newMetricsWriter(benchtypes.SequencerRole, nb.sequencerOptions)
 
 	// Collect metrics in a deferred function to ensure they're always collected
 	defer func() {
 		sequencerMetrics := metricsCollector.GetMetrics()
 		if sequencerMetrics != nil {
+			nb.sequencerBlockMetrics = sequencerMetrics
 			nb.collectedSequencerMetrics = benchtypes.BlockMetricsToSequencerSummary(sequencerMetrics)
 			if err := metricsWriter.Write(sequencerMetrics); err != nil {
 				nb.log.Error("Failed to write sequencer metrics", "error", err)
@@ -115,14 +207,21 @@ func (nb *NetworkBenchmark) benchmarkSequencer(ctx context.Context, l1Chain *l1C
 	}()
 
 	benchmark := newSequencerBenchmark(nb.log, *nb.testConfig, sequencerClient, l1Chain, nb.transactionPayload)
-	return benchmark.Run(ctx, metricsCollector)
+	payloads, firstTestBlock, err := benchmark.Run(ctx, metricsCollector)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nb.recording = recording.New(nb.testConfig.Params, &nb.testConfig.Genesis, firstTestBlock, benchmark.recordedPayloads)
+	return payloads, firstTestBlock, nil
 }
 
 func (nb *NetworkBenchmark) benchmarkValidator(ctx context.Context, payloads []engine.ExecutableData, firstTestBlock uint64, l1Chain *l1Chain) error {
-	validatorClient, err := setupNode(ctx, nb.log, nb.testConfig.Params, nb.validatorOptions, nb.ports)
+	validatorClient, err := setupNode(ctx, nb.log, nb.testConfig.Params, benchtypes.ValidatorRole, nb.validatorOptions, nb.ports)
 	if err != nil {
 		return fmt.Errorf("failed to setup validator node: %w", err)
 	}
+	nb.recordClientVersion(ctx, benchtypes.ValidatorRole, validatorClient)
 
 	defer func() {
 		currentHeader, err := validatorClient.Client().HeaderByNumber(ctx, nil)
@@ -135,13 +234,19 @@ func (nb *NetworkBenchmark) benchmarkValidator(ctx context.Context, payloads []e
 	}()
 
 	// Create metrics collector and writer
-	metricsCollector := validatorClient.MetricsCollector()
-	metricsWriter := metrics.NewFileMetricsWriter(nb.validatorOptions.MetricsPath)
+	metricsCollector, stopScraper := nb.startMetricsScraper(ctx, benchtypes.ValidatorRole, validatorClient, nb.validatorOptions)
+	defer stopScraper()
+	metricsCollector, stopSampler := nb.startResourceSampler(ctx, benchtypes.ValidatorRole, validatorClient, nb.validatorOptions, metricsCollector)
+	defer stopSampler()
+	metricsCollector = nb.measureDataDir(metricsCollector, nb.validatorOptions)
+	metricsCollector = nb.live.Collector(benchtypes.ValidatorRole, metricsCollector)
+	metricsWriter := nb.newMetricsWriter(benchtypes.ValidatorRole, nb.validatorOptions)
 
 	// Collect metrics in a deferred function to ensure they're always collected
 	defer func() {
 		validatorMetrics := metricsCollector.GetMetrics()
 		if validatorMetrics != nil {
+			nb.validatorBlockMetrics = validatorMetrics
 			nb.collectedValidatorMetrics = benchtypes.BlockMetricsToValidatorSummary(validatorMetrics)
 			if err := metricsWriter.Write(validatorMetrics); err != nil {
 				nb.log.Error("Failed to write validator metrics", "error", err)
@@ -150,41 +255,155 @@ func (nb *NetworkBenchmark) benchmarkValidator(ctx context.Context, payloads []e
 	}()
 
 	benchmark := newValidatorBenchmark(nb.log, *nb.testConfig, validatorClient, l1Chain, nb.proofConfig)
-	return benchmark.Run(ctx, payloads, firstTestBlock, metricsCollector)
+	if err := benchmark.Run(ctx, payloads, firstTestBlock, metricsCollector); err != nil {
+		return err
+	}
+
+	if nb.verifyConsistency {
+		nb.consistency, err = nb.checkConsistency(ctx, validatorClient, payloads, firstTestBlock, benchmark.payloadStatuses)
+		if err != nil {
+			return fmt.Errorf("failed to check consistency: %w", err)
+		}
+	}
+	return nil
 }
 
 func (nb *NetworkBenchmark) GetResult() (*benchmark.RunResult, error) {
-	if nb.collectedSequencerMetrics == nil || nb.collectedValidatorMetrics == nil {
+	// the sequencer is not run when replaying recorded payloads
+	replaying := nb.sequencerOptions == nil
+	if (nb.collectedSequencerMetrics == nil && !replaying) || nb.collectedValidatorMetrics == nil {
 		return nil, errors.New("metrics not collected")
 	}
 
-	return &benchmark.RunResult{
-		SequencerMetrics: *nb.collectedSequencerMetrics,
+	result := &benchmark.RunResult{
 		ValidatorMetrics: *nb.collectedValidatorMetrics,
 		Success:          true,
 		Complete:         true,
-	}, nil
+		Consistency:      nb.consistency,
+	}
+	if nb.collectedSequencerMetrics != nil {
+		result.SequencerMetrics = *nb.collectedSequencerMetrics
+	}
+
+	dataDir := make(map[string]*benchmark.DataDirGrowth)
+	if growth := benchmark.NewDataDirGrowth(nb.sequencerBlockMetrics); growth != nil {
+		dataDir[benchtypes.SequencerRole] = growth
+	}
+	if growth := benchmark.NewDataDirGrowth(nb.validatorBlockMetrics); growth != nil {
+		dataDir[benchtypes.ValidatorRole] = growth
+	}
+	if len(dataDir) > 0 {
+		result.DataDir = dataDir
+	}
+
+	return result, nil
+}
+
+// GetBlockMetrics returns the per-block metrics collected from the sequencer
+// and validator.
+func (nb *NetworkBenchmark) GetBlockMetrics() (sequencer []metrics.BlockMetrics, validator []metrics.BlockMetrics) {
+	return nb.sequencerBlockMetrics, nb.validatorBlockMetrics
 }
 
-func setupNode(ctx context.Context, l log.Logger, params benchtypes.RunParams, options *config.InternalClientOptions, portManager portmanager.PortManager) (types.ExecutionClient, error) {
+// startMetricsScraper starts scraping the client's metrics in the background.
+// It returns the metrics collector and a function that stops scraping and
+// writes the scraped series to the metrics directory.
+func (nb *NetworkBenchmark) startMetricsScraper(ctx context.Context, role string, client types.ExecutionClient, options *config.InternalClientOptions) (metrics.Collector, func()) {
+	collector := metrics.NewScrapingCollector(nb.log.With("role", role), client.MetricsScraper(), nb.testConfig.Config.MetricsScrapeInterval())
+	collector.Start(ctx)
+
+	stop := func() {
+		collector.Stop()
+		if err := collector.Write(options.MetricsPath); err != nil {
+			nb.log.Error("Failed to write metrics series", "role", role, "err", err)
+		}
+	}
+	return collector, stop
+}
+
+// startResourceSampler starts sampling the resource usage of the client
+// process if enabled. It returns the metrics collector, wrapped to add
+// per-block resource metrics, and a function that stops the sampler and
+// writes the samples to the metrics directory.
+func (nb *NetworkBenchmark) startResourceSampler(ctx context.Context, role string, client types.ExecutionClient, options *config.InternalClientOptions, collector metrics.Collector) (metrics.Collector, func()) {
+	interval := nb.testConfig.Config.ResourceSampleInterval()
+	pid := client.PID()
+	if interval <= 0 || pid == 0 {
+		return collector, func() {}
+	}
+
+	sampler := metrics.NewResourceSampler(nb.log.With("role", role), pid, interval)
+	sampler.Start(ctx)
+
+	stop := func() {
+		sampler.Stop()
+		if err := sampler.Write(options.MetricsPath); err != nil {
+			nb.log.Error("Failed to write resource samples", "role", role, "err", err)
+		}
+	}
+	return metrics.NewResourceCollector(nb.log, collector, sampler), stop
+}
+
+// measureDataDir wraps the metrics collector to add the size of the client's
+// datadir to every block's metrics if enabled.
+func (nb *NetworkBenchmark) measureDataDir(collector metrics.Collector, options *config.InternalClientOptions) metrics.Collector {
+	if !nb.testConfig.Config.MeasureDataDir() {
+		return collector
+	}
+	return metrics.NewDataDirCollector(nb.log, collector, options.DataDirPath)
+}
+
+// newMetricsWriter returns the writer for a client's per-block metrics:
+// metrics.json, plus the additional outputs enabled by --metrics-output.
+func (nb *NetworkBenchmark) newMetricsWriter(role string, options *config.InternalClientOptions) metrics.MetricsWriter {
+	writers := metrics.NewMultiMetricsWriter(metrics.NewFileMetricsWriter(options.MetricsPath))
+	for _, output := range nb.testConfig.Config.MetricsOutputs() {
+		switch output {
+		case metrics.MetricsOutputCSV:
+			writers = append(writers, metrics.NewCSVMetricsWriter(options.MetricsPath))
+		case metrics.MetricsOutputParquet:
+			writers = append(writers, metrics.NewParquetMetricsWriter(options.MetricsPath))
+		case metrics.MetricsOutputRemoteWrite:
+			params := nb.testConfig.Params
+			writers = append(writers, metrics.NewRemoteWriteMetricsWriter(nb.testConfig.Config.MetricsRemoteWriteURL(), map[string]string{
+				"role":          role,
+				"node_type":     params.NodeTypeFor(role),
+				"test":          params.Name,
+				"benchmark_run": params.BenchmarkRunID,
+				"run":           nb.testConfig.RunID,
+				"repetition":    strconv.Itoa(nb.testConfig.Repetition),
+				"payload":       params.PayloadID,
+			}))
+		}
+	}
+	return writers
+}
+
+// recordClientVersion records the version of the client run for the given role.
+func (nb *NetworkBenchmark) recordClientVersion(ctx context.Context, role string, client types.ExecutionClient) {
+	version := client.Version(ctx)
+	version.NodeType = nb.testConfig.Params.NodeTypeFor(role)
+	nb.log.Info("Client version", "role", role, "nodeType", version.NodeType, "version", version.ClientVersion, "sha256", version.BinarySHA256)
+	nb.clientVersions[role] = version
+}
+
+// ClientVersions returns the versions of the clients run, keyed by role.
+func (nb *NetworkBenchmark) ClientVersions() map[string]*types.ClientVersion {
+	return nb.clientVersions
+}
+
+func setupNode(ctx context.Context, l log.Logger, params benchtypes.RunParams, role string, options *config.InternalClientOptions, portManager portmanager.PortManager) (types.ExecutionClient, error) {
 	if options == nil {
 		return nil, errors.New("client options cannot be nil")
 	}
 
-	var nodeType clients.Client
-	switch params.NodeType {
-	case "geth":
-		nodeType = clients.Geth
-	case "reth":
-		nodeType = clients.Reth
-	case "rbuilder":
-		nodeType = clients.Rbuilder
-	default:
-		return nil, fmt.Errorf("unsupported node type: %s", params.NodeType)
-	}
+	nodeType := params.NodeTypeFor(role)
 
-	clientLogger := l.With("nodeType", params.NodeType)
-	client := clients.NewClient(nodeType, clientLogger, options, portManager)
+	clientLogger := l.With("nodeType", nodeType, "role", role)
+	client, err := clients.NewClient(nodeType, clientLogger, options, portManager)
+	if err != nil {
+		return nil, err
+	}
 
 	logPath := path.Join(options.TestDirPath, ExecutionLayerLogFileName)
 	fileWriter, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
@@ -198,6 +417,12 @@ func setupNode(ctx context.Context, l log.Logger, params benchtypes.RunParams, o
 	runtimeConfig := &types.RuntimeConfig{
 		Stdout: stdoutLogger,
 		Stderr: stderrLogger,
+		Args:   params.ClientArgsFor(role),
+	}
+	// runs that set env vars start their clients from a sanitized environment
+	// so the runner's shell does not leak into them; other runs inherit it
+	if params.HasEnv() {
+		runtimeConfig.Env = common.MergeEnv(common.BaseEnv(), params.EnvFor(role))
 	}
 
 	if err := client.Run(ctx, runtimeConfig); err != nil {
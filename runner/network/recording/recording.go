@@ -0,0 +1,147 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/base/base-bench/runner/benchmark"
+	benchtypes "github.com/base/base-bench/runner/network/types"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/core"
+)
+
+const (
+	// FileName is the name of the recording in each run's output directory.
+	FileName = "payloads.jsonl"
+
+	// FormatVersion is the version of the recording format. It is bumped
+	// whenever older recordings can no longer be replayed.
+	FormatVersion = 1
+)
+
+// Header describes the chain the recorded payloads were built on. It is the
+// first line of a recording.
+type Header struct {
+	Version int                  `json:"version"`
+	Params  benchtypes.RunParams `json:"params"`
+	Genesis *core.Genesis        `json:"genesis"`
+	// Snapshot is the snapshot the clients' datadirs were restored from, if
+	// any. Replaying requires the snapshot to restore the same chain head.
+	Snapshot *benchmark.SnapshotDefinition `json:"snapshot,omitempty"`
+	// FirstTestBlock is the number of the first benchmark block. Earlier
+	// payloads are setup blocks.
+	FirstTestBlock uint64 `json:"firstTestBlock"`
+}
+
+// Payload is a block built by the sequencer together with the payload
+// attributes it was built from.
+type Payload struct {
+	Attributes       *eth.PayloadAttributes `json:"attributes"`
+	ExecutionPayload *engine.ExecutableData `json:"executionPayload"`
+}
+
+// Recording holds every payload built by the sequencer during a run, so that
+// the blocks can be replayed into other clients later.
+type Recording struct {
+	Header
+	Payloads []Payload
+}
+
+// New creates a recording of payloads built on the given chain.
+func New(params benchtypes.RunParams, genesis *core.Genesis, firstTestBlock uint64, payloads []Payload) *Recording {
+	return &Recording{
+		Header: Header{
+			Version:        FormatVersion,
+			Params:         params,
+			Genesis:        genesis,
+			FirstTestBlock: firstTestBlock,
+		},
+		Payloads: payloads,
+	}
+}
+
+// ExecutionPayloads returns the recorded blocks in the order they were built.
+func (r *Recording) ExecutionPayloads() []engine.ExecutableData {
+	payloads := make([]engine.ExecutableData, len(r.Payloads))
+	for i, payload := range r.Payloads {
+		payloads[i] = *payload.ExecutionPayload
+	}
+	return payloads
+}
+
+// Write writes the recording to the given path as JSON lines: the header
+// followed by one line per payload.
+func (r *Recording) Write(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(r.Header); err != nil {
+		return fmt.Errorf("failed to write recording header: %w", err)
+	}
+	for _, payload := range r.Payloads {
+		if err := encoder.Encode(payload); err != nil {
+			return fmt.Errorf("failed to write payload %d: %w", payload.ExecutionPayload.Number, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return f.Close()
+}
+
+// Read reads a recording written by Write.
+func Read(filePath string) (*Recording, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	decoder := json.NewDecoder(bufio.NewReader(f))
+
+	var recording Recording
+	if err := decoder.Decode(&recording.Header); err != nil {
+		return nil, fmt.Errorf("failed to read recording header: %w", err)
+	}
+	if recording.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported recording version %d, expected %d", recording.Version, FormatVersion)
+	}
+	if recording.Genesis == nil {
+		return nil, errors.New("recording has no genesis")
+	}
+
+	for {
+		var payload Payload
+		err := decoder.Decode(&payload)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload %d: %w", len(recording.Payloads), err)
+		}
+		if payload.ExecutionPayload == nil {
+			return nil, fmt.Errorf("payload %d has no execution payload", len(recording.Payloads))
+		}
+		recording.Payloads = append(recording.Payloads, payload)
+	}
+
+	if len(recording.Payloads) == 0 {
+		return nil, errors.New("recording has no payloads")
+	}
+	return &recording, nil
+}
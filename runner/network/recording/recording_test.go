@@ -0,0 +1,123 @@
+package recording
+
+import (
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/base/base-bench/runner/benchmark"
+	benchtypes "github.com/base/base-bench/runner/network/types"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecording() *Recording {
+	genesis := &core.Genesis{
+		Config:     params.TestChainConfig,
+		Timestamp:  1700000000,
+		GasLimit:   30_000_000,
+		Difficulty: big.NewInt(0),
+		Alloc: types.GenesisAlloc{
+			common.HexToAddress("0x01"): {Balance: big.NewInt(1e18)},
+		},
+	}
+
+	gasLimit := eth.Uint64Quantity(30_000_000)
+	payloads := make([]Payload, 0, 3)
+	for number := uint64(1); number <= 3; number++ {
+		payloads = append(payloads, Payload{
+			Attributes: &eth.PayloadAttributes{
+				Timestamp:    eth.Uint64Quantity(1700000000 + number),
+				Transactions: []eth.Data{hexutil.Bytes{0x7e, byte(number)}},
+				GasLimit:     &gasLimit,
+			},
+			ExecutionPayload: &engine.ExecutableData{
+				ParentHash:    common.Hash{byte(number - 1)},
+				LogsBloom:     make([]byte, types.BloomByteLength),
+				Number:        number,
+				GasLimit:      uint64(gasLimit),
+				GasUsed:       21000 * number,
+				Timestamp:     1700000000 + number,
+				ExtraData:     []byte{0x00},
+				BaseFeePerGas: big.NewInt(1),
+				BlockHash:     common.Hash{byte(number)},
+				Transactions:  [][]byte{{0x7e, byte(number)}},
+				Withdrawals:   []*types.Withdrawal{},
+			},
+		})
+	}
+
+	runParams := benchtypes.RunParams{
+		NodeType:  "geth",
+		GasLimit:  uint64(gasLimit),
+		PayloadID: "transfer-only",
+		Name:      "test",
+		BlockTime: 2 * time.Second,
+		NumBlocks: 1,
+		ClientArgs: map[string][]string{
+			"reth": {"--engine.legacy"},
+		},
+	}
+	return New(runParams, genesis, 3, payloads)
+}
+
+func TestWriteRead(t *testing.T) {
+	rec := testRecording()
+	genesisFile := "genesis.json"
+	rec.Snapshot = &benchmark.SnapshotDefinition{
+		Command:     "./scripts/snapshot.sh",
+		GenesisFile: &genesisFile,
+	}
+
+	file := path.Join(t.TempDir(), FileName)
+	require.NoError(t, rec.Write(file))
+
+	read, err := Read(file)
+	require.NoError(t, err)
+
+	require.Equal(t, FormatVersion, read.Version)
+	require.Equal(t, rec.Params, read.Params)
+	require.Equal(t, rec.Snapshot, read.Snapshot)
+	require.Equal(t, uint64(3), read.FirstTestBlock)
+	require.Equal(t, rec.Genesis.ToBlock().Hash(), read.Genesis.ToBlock().Hash())
+
+	require.Len(t, read.Payloads, 3)
+	for i, payload := range read.Payloads {
+		require.Equal(t, rec.Payloads[i].Attributes, payload.Attributes)
+		require.Equal(t, rec.Payloads[i].ExecutionPayload, payload.ExecutionPayload)
+	}
+	require.Equal(t, rec.ExecutionPayloads(), read.ExecutionPayloads())
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	rec := testRecording()
+	rec.Version = FormatVersion + 1
+
+	file := path.Join(t.TempDir(), FileName)
+	require.NoError(t, rec.Write(file))
+
+	_, err := Read(file)
+	require.ErrorContains(t, err, "unsupported recording version")
+}
+
+func TestReadNoPayloads(t *testing.T) {
+	rec := testRecording()
+	rec.Payloads = nil
+
+	file := path.Join(t.TempDir(), FileName)
+	require.NoError(t, rec.Write(file))
+
+	_, err := Read(file)
+	require.ErrorContains(t, err, "recording has no payloads")
+
+	_, err = Read(path.Join(t.TempDir(), "missing.jsonl"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/base/base-bench/runner/network/consensus"
 	"github.com/base/base-bench/runner/network/mempool"
 	"github.com/base/base-bench/runner/network/proofprogram/fakel1"
+	"github.com/base/base-bench/runner/network/recording"
 	benchtypes "github.com/base/base-bench/runner/network/types"
 	"github.com/base/base-bench/runner/payload"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
@@ -29,6 +30,10 @@ type sequencerBenchmark struct {
 	config             benchtypes.TestConfig
 	l1Chain            *l1Chain
 	transactionPayload payload.Definition
+
+	// recordedPayloads are the built payloads with the attributes they were
+	// built from, set once all blocks are built.
+	recordedPayloads []recording.Payload
 }
 
 func newSequencerBenchmark(log log.Logger, config benchtypes.TestConfig, sequencerClient types.ExecutionClient, l1Chain *l1Chain, transactionPayload payload.Definition) *sequencerBenchmark {
@@ -132,6 +137,9 @@ func (nb *sequencerBenchmark) Run(ctx context.Context, metricsCollector metrics.
 
 	params := nb.config.Params
 	sequencerClient := nb.sequencerClient
+	if params.BlockTime < time.Second {
+		nb.log.Warn("L2 timestamps advance by one second per block and run ahead of wall-clock time with sub-second block times", "blockTime", params.BlockTime)
+	}
 	defer func() {
 		err := transactionWorker.Stop(ctx)
 		if err != nil {
@@ -191,6 +199,7 @@ func (nb *sequencerBenchmark) Run(ctx context.Context, metricsCollector metrics.
 		}, headBlockHash, headBlockNumber, l1Chain, nb.config.BatcherAddr())
 
 		payloads := make([]engine.ExecutableData, 0)
+		recordedPayloads := make([]recording.Payload, 0)
 
 	setupLoop:
 		for {
@@ -202,6 +211,10 @@ func (nb *sequencerBenchmark) Run(ctx context.Context, metricsCollector metrics.
 			}
 
 			payloads = append(payloads, *payload)
+			recordedPayloads = append(recordedPayloads, recording.Payload{
+				Attributes:       consensusClient.PayloadAttributes(),
+				ExecutionPayload: payload,
+			})
 			select {
 			case <-setupComplete:
 				break setupLoop
@@ -226,24 +239,28 @@ func (nb *sequencerBenchmark) Run(ctx context.Context, metricsCollector metrics.
 				return
 			}
 
+			blockMetrics.StartTime = time.Now()
 			payload, err := consensusClient.Propose(benchmarkCtx, blockMetrics, false)
 			if err != nil {
 				errChan <- err
 				return
 			}
+			blockMetrics.EndTime = time.Now()
 
 			if payload == nil {
 				errChan <- errors.New("received nil payload from consensus client")
 				return
 			}
 
-			time.Sleep(1000 * time.Millisecond)
-
 			err = metricsCollector.Collect(benchmarkCtx, blockMetrics)
 			if err != nil {
 				nb.log.Error("Failed to collect metrics", "error", err)
 			}
 			payloads = append(payloads, *payload)
+			recordedPayloads = append(recordedPayloads, recording.Payload{
+				Attributes:       consensusClient.PayloadAttributes(),
+				ExecutionPayload: payload,
+			})
 		}
 
 		err = consensusClient.Stop(benchmarkCtx)
@@ -251,6 +268,7 @@ func (nb *sequencerBenchmark) Run(ctx context.Context, metricsCollector metrics.
 			nb.log.Warn("failed to stop consensus client", "err", err)
 		}
 
+		nb.recordedPayloads = recordedPayloads
 		payloadResult <- payloads
 	}()
 
@@ -2,7 +2,11 @@ package types
 
 import (
 	"crypto/ecdsa"
+	"fmt"
+	"maps"
 	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/base/base-bench/runner/config"
@@ -30,7 +34,11 @@ var _ ethTypes.BlockType = IsthmusBlockType{}
 
 // TestConfig holds all configuration needed for a benchmark test
 type TestConfig struct {
-	Params     RunParams
+	Params RunParams
+	// RunID identifies the run by its output directory. Repetition is the
+	// index of the run within its matrix cell if the cell is repeated.
+	RunID      string
+	Repetition int
 	Config     config.Config
 	Genesis    core.Genesis
 	BatcherKey ecdsa.PrivateKey
@@ -52,16 +60,145 @@ func (c *TestConfig) BatcherAddr() common.Address {
 
 // Params is the parameters for a single benchmark run.
 type RunParams struct {
-	NodeType       string
-	GasLimit       uint64
-	PayloadID      string
-	BenchmarkRunID string
-	Name           string
-	Description    string
-	BlockTime      time.Duration
-	Env            map[string]string
-	NumBlocks      int
-	Tags           map[string]string
+	NodeType string
+	// SequencerNodeType and ValidatorNodeType override NodeType for a
+	// single role when set.
+	SequencerNodeType string
+	ValidatorNodeType string
+	GasLimit          uint64
+	PayloadID         string
+	BenchmarkRunID    string
+	Name              string
+	Description       string
+	BlockTime         time.Duration
+	Env               map[string]string
+	NumBlocks         int
+	Tags              map[string]string
+	// ClientArgs are extra CLI args passed to the execution clients, keyed by
+	// a selector: "" for all clients, a node type, a role, or
+	// "<node_type>/<role>".
+	ClientArgs map[string][]string
+	// ClientEnv are env vars for specific execution clients, keyed by the
+	// same selectors as ClientArgs. Env applies to all clients.
+	ClientEnv map[string]map[string]string
+}
+
+const (
+	SequencerRole = "sequencer"
+	ValidatorRole = "validator"
+)
+
+// NodeTypeFor returns the node type of the client running in the given role.
+func (p RunParams) NodeTypeFor(role string) string {
+	switch {
+	case role == SequencerRole && p.SequencerNodeType != "":
+		return p.SequencerNodeType
+	case role == ValidatorRole && p.ValidatorNodeType != "":
+		return p.ValidatorNodeType
+	}
+	return p.NodeType
+}
+
+// NodeTypeLabel describes the node types of both clients, e.g. "rbuilder/reth"
+// when the sequencer and validator run different clients.
+func (p RunParams) NodeTypeLabel() string {
+	sequencerNodeType := p.NodeTypeFor(SequencerRole)
+	validatorNodeType := p.NodeTypeFor(ValidatorRole)
+	if sequencerNodeType == validatorNodeType {
+		return sequencerNodeType
+	}
+	return sequencerNodeType + "/" + validatorNodeType
+}
+
+// ClientArgsFor returns the extra CLI args for the client running in the given
+// role. Args for all clients come first, followed by node type, role and node
+// type and role specific args, so more specific args come last.
+func (p RunParams) ClientArgsFor(role string) []string {
+	nodeType := p.NodeTypeFor(role)
+	args := make([]string, 0)
+	for _, selector := range []string{"", nodeType, role, nodeType + "/" + role} {
+		args = append(args, p.ClientArgs[selector]...)
+	}
+	return args
+}
+
+// EnvFor returns the env vars for the client running in the given role. More
+// specific selectors override less specific ones.
+func (p RunParams) EnvFor(role string) map[string]string {
+	nodeType := p.NodeTypeFor(role)
+	env := make(map[string]string, len(p.Env))
+	maps.Copy(env, p.Env)
+	for _, selector := range []string{nodeType, role, nodeType + "/" + role} {
+		maps.Copy(env, p.ClientEnv[selector])
+	}
+	return env
+}
+
+// HasEnv returns whether the run sets env vars on any client.
+func (p RunParams) HasEnv() bool {
+	return len(p.Env) > 0 || len(p.ClientEnv) > 0
+}
+
+// formatEnv formats env vars as sorted "KEY=value" pairs separated by ";".
+func formatEnv(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = key + "=" + env[key]
+	}
+	return strings.Join(entries, ";")
+}
+
+// envString formats the env vars so runs can be grouped by them.
+func (p RunParams) envString() string {
+	parts := make([]string, 0, len(p.ClientEnv)+1)
+	if len(p.Env) > 0 {
+		parts = append(parts, formatEnv(p.Env))
+	}
+
+	selectors := make([]string, 0, len(p.ClientEnv))
+	for selector := range p.ClientEnv {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+	for _, selector := range selectors {
+		parts = append(parts, fmt.Sprintf("%s: %s", selector, formatEnv(p.ClientEnv[selector])))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// clientArgsString formats the client args so runs can be grouped by them.
+func (p RunParams) clientArgsString() string {
+	selectors := make([]string, 0, len(p.ClientArgs))
+	for selector := range p.ClientArgs {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	parts := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		args := strings.Join(p.ClientArgs[selector], " ")
+		if selector == "" {
+			parts = append(parts, args)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", selector, args))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// TimestampIncrement returns the number of seconds L2 timestamps advance per
+// block. Timestamps have second granularity and must strictly increase, so
+// sub-second block times still advance the timestamp by one second and L2
+// time runs ahead of wall-clock time. Block times above one second are whole
+// seconds.
+func TimestampIncrement(blockTime time.Duration) uint64 {
+	return max(1, uint64(blockTime/time.Second))
 }
 
 func (p RunParams) ToConfig() map[string]interface{} {
@@ -73,6 +210,25 @@ func (p RunParams) ToConfig() map[string]interface{} {
 		"BlockTimeMilliseconds": p.BlockTime.Milliseconds(),
 	}
 
+	// record both node types when they are set separately. NodeType keeps
+	// the configured value so existing filters on it still match.
+	if p.SequencerNodeType != "" || p.ValidatorNodeType != "" {
+		params["SequencerNodeType"] = p.NodeTypeFor(SequencerRole)
+		params["ValidatorNodeType"] = p.NodeTypeFor(ValidatorRole)
+	}
+
+	if clientArgs := p.clientArgsString(); clientArgs != "" {
+		params["ClientArgs"] = clientArgs
+	}
+
+	// record the env vars each client ran with as well, since selectors can
+	// override each other
+	if env := p.envString(); env != "" {
+		params["Env"] = env
+		params["SequencerEnv"] = formatEnv(p.EnvFor(SequencerRole))
+		params["ValidatorEnv"] = formatEnv(p.EnvFor(ValidatorRole))
+	}
+
 	for k, v := range p.Tags {
 		params[k] = v
 	}
@@ -85,19 +241,8 @@ func (p RunParams) ClientOptions(prevClientOptions config.ClientOptions) config.
 	return prevClientOptions
 }
 
-func getAverage(metrics []metrics.BlockMetrics, metricName string) float64 {
-	var total float64
-	var count int
-	for _, metric := range metrics {
-		if value, ok := metric.GetMetricFloat(metricName); ok {
-			total += value
-			count++
-		}
-	}
-	if count == 0 {
-		return 0
-	}
-	return total / float64(count)
+func getDistribution(blockMetrics []metrics.BlockMetrics, metricName string) metrics.Distribution {
+	return metrics.NewDistribution(metrics.GetMetricValues(blockMetrics, metricName))
 }
 
 const (
@@ -108,6 +253,7 @@ const (
 	GasPerBlockMetric             = "gas/per_block"
 	GasPerSecondMetric            = "gas/per_second"
 	TransactionsPerBlockMetric    = "transactions/per_block"
+	TransactionsSentMetric        = "transactions/sent"
 )
 
 type SequencerKeyMetrics struct {
@@ -124,34 +270,47 @@ type ValidatorKeyMetrics struct {
 
 type CommonKeyMetrics struct {
 	AverageGasPerSecond float64 `json:"gasPerSecond"`
+	// Distributions holds the percentile summary of each key metric, keyed by
+	// metric name. Latencies are in seconds.
+	Distributions map[string]metrics.Distribution `json:"distributions,omitempty"`
 }
 
 // BlockMetricsToValidatorSummary converts block metrics to a validator summary.
-func BlockMetricsToValidatorSummary(metrics []metrics.BlockMetrics) *ValidatorKeyMetrics {
-	averageNewPayloadLatency := getAverage(metrics, NewPayloadLatencyMetric)
-	averageGasPerSecond := getAverage(metrics, GasPerSecondMetric)
+func BlockMetricsToValidatorSummary(blockMetrics []metrics.BlockMetrics) *ValidatorKeyMetrics {
+	newPayloadLatency := getDistribution(blockMetrics, NewPayloadLatencyMetric)
+	gasPerSecond := getDistribution(blockMetrics, GasPerSecondMetric)
 
 	return &ValidatorKeyMetrics{
-		AverageNewPayloadLatency: averageNewPayloadLatency,
+		AverageNewPayloadLatency: newPayloadLatency.Mean,
 		CommonKeyMetrics: CommonKeyMetrics{
-			AverageGasPerSecond: averageGasPerSecond,
+			AverageGasPerSecond: gasPerSecond.Mean,
+			Distributions: map[string]metrics.Distribution{
+				NewPayloadLatencyMetric: newPayloadLatency,
+				GasPerSecondMetric:      gasPerSecond,
+			},
 		},
 	}
 }
 
 // BlockMetricsToSequencerSummary converts block metrics to a sequencer summary.
-func BlockMetricsToSequencerSummary(metrics []metrics.BlockMetrics) *SequencerKeyMetrics {
-	averageUpdateForkChoiceLatency := getAverage(metrics, UpdateForkChoiceLatencyMetric)
-	averageSendTxsLatency := getAverage(metrics, SendTxsLatencyMetric)
-	averageGetPayloadLatency := getAverage(metrics, GetPayloadLatencyMetric)
-	averageGasPerSecond := getAverage(metrics, GasPerSecondMetric)
+func BlockMetricsToSequencerSummary(blockMetrics []metrics.BlockMetrics) *SequencerKeyMetrics {
+	updateForkChoiceLatency := getDistribution(blockMetrics, UpdateForkChoiceLatencyMetric)
+	sendTxsLatency := getDistribution(blockMetrics, SendTxsLatencyMetric)
+	getPayloadLatency := getDistribution(blockMetrics, GetPayloadLatencyMetric)
+	gasPerSecond := getDistribution(blockMetrics, GasPerSecondMetric)
 
 	return &SequencerKeyMetrics{
-		AverageFCULatency:        averageUpdateForkChoiceLatency,
-		AverageSendTxsLatency:    averageSendTxsLatency,
-		AverageGetPayloadLatency: averageGetPayloadLatency,
+		AverageFCULatency:        updateForkChoiceLatency.Mean,
+		AverageSendTxsLatency:    sendTxsLatency.Mean,
+		AverageGetPayloadLatency: getPayloadLatency.Mean,
 		CommonKeyMetrics: CommonKeyMetrics{
-			AverageGasPerSecond: averageGasPerSecond,
+			AverageGasPerSecond: gasPerSecond.Mean,
+			Distributions: map[string]metrics.Distribution{
+				UpdateForkChoiceLatencyMetric: updateForkChoiceLatency,
+				SendTxsLatencyMetric:          sendTxsLatency,
+				GetPayloadLatencyMetric:       getPayloadLatency,
+				GasPerSecondMetric:            gasPerSecond,
+			},
 		},
 	}
 }
@@ -25,6 +25,10 @@ type validatorBenchmark struct {
 	config          benchtypes.TestConfig
 	proofConfig     *benchmark.ProofProgramOptions
 	l1Chain         *l1Chain
+
+	// payloadStatuses holds the status of every payload the validator did
+	// not accept as valid, keyed by block number.
+	payloadStatuses map[uint64]engine.PayloadStatusV1
 }
 
 func newValidatorBenchmark(log log.Logger, config benchtypes.TestConfig, validatorClient types.ExecutionClient, l1Chain *l1Chain, proofConfig *benchmark.ProofProgramOptions) *validatorBenchmark {
@@ -49,7 +53,7 @@ func (vb *validatorBenchmark) benchmarkFaultProofProgram(ctx context.Context, pa
 		return fmt.Errorf("proof program binary does not exist at %s", binaryPath)
 	}
 
-	opProgramBenchmark := NewOPProgramBenchmark(&vb.config.Genesis, vb.log, binaryPath, vb.validatorClient.ClientURL(), l1Chain, batcherKey)
+	opProgramBenchmark := NewOPProgramBenchmark(&vb.config.Genesis, vb.log, binaryPath, vb.validatorClient.ClientURL(), l1Chain, batcherKey, vb.config.Params.BlockTime)
 
 	return opProgramBenchmark.Run(ctx, payloads, firstTestBlock)
 }
@@ -68,6 +72,7 @@ func (vb *validatorBenchmark) Run(ctx context.Context, payloads []engine.Executa
 	}, headBlockHash, headBlockNumber)
 
 	err = consensusClient.Start(ctx, payloads, metricsCollector, firstTestBlock)
+	vb.payloadStatuses = consensusClient.PayloadStatuses()
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return err
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/base/base-bench/runner/benchmark"
+	"github.com/base/base-bench/runner/clients/common"
+	"github.com/base/base-bench/runner/payload"
+)
+
+// cpuSetsForSlots splits the given CPUs into one set of consecutive CPUs per
+// execution slot. A parallelism of 1 returns a single slot without pinning.
+func cpuSetsForSlots(parallelism int, cpus []int) ([]string, error) {
+	if parallelism <= 1 {
+		return []string{""}, nil
+	}
+	if len(cpus) < parallelism {
+		return nil, fmt.Errorf("parallelism %d exceeds the %d available CPU cores", parallelism, len(cpus))
+	}
+	if _, err := exec.LookPath(common.TasksetBin); err != nil {
+		return nil, fmt.Errorf("parallelism %d requires %s to pin clients to CPUs: %w", parallelism, common.TasksetBin, err)
+	}
+
+	perSlot := len(cpus) / parallelism
+	cpuSets := make([]string, parallelism)
+	for slot := range cpuSets {
+		cpuSets[slot] = formatCPUList(cpus[slot*perSlot : (slot+1)*perSlot])
+	}
+	return cpuSets, nil
+}
+
+// formatCPUList formats sorted CPU IDs as a taskset CPU list, e.g. "8-11,14".
+func formatCPUList(cpus []int) string {
+	parts := make([]string, 0, len(cpus))
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(cpus[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// requiresSerialExecution returns true if runs of the test plan share state
+// that prevents them from executing concurrently: snapshots are restored into
+// a shared datadir, the proof program shares its L1 chain and tx-fuzz binds a
+// fixed proxy port.
+func requiresSerialExecution(testPlan benchmark.TestPlan, transactionPayloads map[string]payload.Definition) bool {
+	if testPlan.Snapshot != nil || testPlan.ProofProgram != nil {
+		return true
+	}
+	for _, run := range testPlan.Runs {
+		if transactionPayloads[run.Params.PayloadID].Type == "tx-fuzz" {
+			return true
+		}
+	}
+	return false
+}
+
+// runParallel executes the runs of a test plan on a pool of execution slots,
+// each pinned to its own CPU set. Runs are assigned metadata indices starting
+// at firstIdx. It returns the first error returned by executeRun after all
+// started runs have finished.
+func (s *service) runParallel(ctx context.Context, testPlan benchmark.TestPlan, firstIdx int, cpuSets []string, skipCompleted func(idx int) bool, executeRun func(idx int, testPlan benchmark.TestPlan, c benchmark.TestRun, slot int, cpuSet string) error) error {
+	slots := make(chan int, len(cpuSets))
+	for slot := range cpuSets {
+		slots <- slot
+	}
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var runErr error
+
+runLoop:
+	for i, c := range testPlan.Runs {
+		idx := firstIdx + i
+		if skipCompleted(idx) {
+			continue
+		}
+
+		var slot int
+		select {
+		case <-ctx.Done():
+			// if ctx is done, stop starting new tests
+			break runLoop
+		case slot = <-slots:
+		}
+
+		wg.Add(1)
+		go func(idx int, c benchmark.TestRun, slot int) {
+			defer wg.Done()
+			defer func() { slots <- slot }()
+
+			if err := executeRun(idx, testPlan, c, slot, cpuSets[slot]); err != nil {
+				errOnce.Do(func() { runErr = err })
+			}
+		}(idx, c, slot)
+	}
+
+	wg.Wait()
+	return runErr
+}
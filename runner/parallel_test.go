@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCPUList(t *testing.T) {
+	tests := []struct {
+		cpus []int
+		want string
+	}{
+		{cpus: []int{3}, want: "3"},
+		{cpus: []int{8, 9, 10, 11}, want: "8-11"},
+		{cpus: []int{0, 2, 4}, want: "0,2,4"},
+		{cpus: []int{8, 9, 12, 14, 15}, want: "8-9,12,14-15"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, formatCPUList(tt.cpus))
+	}
+}
+
+func TestCPUSetsForSlots(t *testing.T) {
+	cpuSets, err := cpuSetsForSlots(1, []int{8, 9})
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, cpuSets)
+
+	_, err = cpuSetsForSlots(4, []int{8, 9})
+	require.ErrorContains(t, err, "exceeds the 2 available CPU cores")
+}
@@ -3,6 +3,7 @@ package payload
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	clienttypes "github.com/base/base-bench/runner/clients/types"
 	benchtypes "github.com/base/base-bench/runner/network/types"
@@ -54,6 +55,22 @@ type Definition struct {
 	Params any     `yaml:"-"`
 }
 
+// NewDefinitionParams returns an empty params struct for the given payload
+// type.
+func NewDefinitionParams(payloadType string) (any, error) {
+	switch payloadType {
+	case "transfer-only":
+		return &transferonly.TransferOnlyPayloadDefinition{}, nil
+	case "tx-fuzz":
+		return &txfuzz.TxFuzzPayloadDefinition{}, nil
+	case "contract":
+		return &contract.ContractPayloadDefinition{}, nil
+	case "simulator":
+		return &simulator.SimulatorPayloadDefinition{}, nil
+	}
+	return nil, fmt.Errorf("invalid payload type %q", payloadType)
+}
+
 func (t *Definition) UnmarshalYAML(node *yaml.Node) error {
 	type txPayloadWithoutParams struct {
 		Name string `yaml:"name"`
@@ -71,16 +88,9 @@ func (t *Definition) UnmarshalYAML(node *yaml.Node) error {
 	t.ID = txPayload.ID
 	t.Type = txPayload.Type
 
-	params := interface{}(nil)
-	switch t.Type {
-	case "transfer-only":
-		params = &transferonly.TransferOnlyPayloadDefinition{}
-	case "tx-fuzz":
-		params = &txfuzz.TxFuzzPayloadDefinition{}
-	case "contract":
-		params = &contract.ContractPayloadDefinition{}
-	case "simulator":
-		params = &simulator.SimulatorPayloadDefinition{}
+	params, err := NewDefinitionParams(t.Type)
+	if err != nil {
+		return err
 	}
 
 	err = node.Decode(params)
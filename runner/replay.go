@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/pkg/errors"
+
+	"github.com/base/base-bench/runner/benchmark"
+	clienttypes "github.com/base/base-bench/runner/clients/types"
+	"github.com/base/base-bench/runner/config"
+	"github.com/base/base-bench/runner/network"
+	"github.com/base/base-bench/runner/network/recording"
+	"github.com/base/base-bench/runner/network/types"
+	"github.com/base/base-bench/runner/payload"
+)
+
+// ReplayService benchmarks validator clients against recorded payloads.
+type ReplayService interface {
+	Replay(ctx context.Context, recordingPath string, nodeType string) error
+}
+
+func NewReplayService(version string, cfg config.Config, log log.Logger) ReplayService {
+	return newService(version, cfg, log)
+}
+
+// Replay syncs the payloads of a recording into a fresh validator client of
+// the given node type, or the recorded validator node type if empty. The run
+// is added to metadata.json in the output dir like any other run.
+func (s *service) Replay(ctx context.Context, recordingPath string, nodeType string) error {
+	s.log.Info("Starting replay", "recording", recordingPath)
+
+	rec, err := recording.Read(recordingPath)
+	if err != nil {
+		return errors.Wrap(err, "invalid payload recording")
+	}
+
+	stopTelemetry, err := s.startTelemetry(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopTelemetry()
+
+	benchmarkRunID, err := generateBenchmarkRunID()
+	if err != nil {
+		return err
+	}
+
+	params := rec.Params
+	params.BenchmarkRunID = benchmarkRunID
+	if nodeType != "" {
+		params.ValidatorNodeType = nodeType
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("replay-%d", now.UnixMicro())
+	run := benchmark.Run{
+		ID:              id,
+		SourceFile:      recordingPath,
+		OutputDir:       id,
+		TestName:        params.Name,
+		TestDescription: params.Description,
+		TestConfig:      params.ToConfig(),
+		CreatedAt:       &now,
+	}
+
+	outputDir := path.Join(s.config.OutputDir(), run.OutputDir)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create output directory")
+	}
+
+	host := benchmark.CollectHostInfo(s.config.DataDir(), s.version)
+	if err := writeHostInfo(outputDir, host); err != nil {
+		return err
+	}
+	run.Host = host
+
+	s.log.Info("Replaying payloads", "id", benchmarkRunID, "nodeType", params.NodeTypeFor(types.ValidatorRole), "payloads", len(rec.Payloads), "firstTestBlock", rec.FirstTestBlock)
+
+	liveRun := s.live.StartRun(params, run.OutputDir)
+	result, clientVersions, replayErr := s.replayTest(ctx, rec, params, outputDir, liveRun)
+	liveRun.Finish(replayErr == nil)
+	if replayErr != nil {
+		s.log.Error("Failed to replay payloads", "err", replayErr)
+		result = &benchmark.RunResult{
+			Success:  false,
+			Complete: true,
+		}
+	}
+	run.Clients = clientVersions
+
+	metadata := benchmark.RunGroup{
+		Runs: []benchmark.Run{run},
+	}
+	metadata.AddResult(0, *result)
+	if err := s.writeTestMetadata(metadata); err != nil {
+		return errors.Wrap(err, "failed to write test metadata")
+	}
+
+	if replayErr != nil {
+		return replayErr
+	}
+
+	if result.HasDivergence() {
+		return errors.New("validator diverged from the recorded payloads")
+	}
+
+	s.log.Info("Finished replay", "outputDir", outputDir)
+	return nil
+}
+
+func (s *service) replayTest(ctx context.Context, rec *recording.Recording, params types.RunParams, outputDir string, live *network.LiveRun) (*benchmark.RunResult, map[string]*clienttypes.ClientVersion, error) {
+	testName := fmt.Sprintf("%s-%s-test", path.Base(outputDir), params.NodeTypeFor(types.ValidatorRole))
+	validatorTestDir := path.Join(s.config.DataDir(), fmt.Sprintf("%s-validator", testName))
+
+	validatorOptions, err := s.setupInternalDirectories(validatorTestDir, params, rec.Genesis, rec.Snapshot, types.ValidatorRole)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup internal directories")
+	}
+	validatorOptions.DebugAPI = true
+
+	defer func() {
+		// clean up test directory
+		err := os.RemoveAll(validatorTestDir)
+		if err != nil {
+			log.Error("failed to remove test directory", "err", err)
+		}
+	}()
+
+	config := &types.TestConfig{
+		Params:  params,
+		RunID:   path.Base(outputDir),
+		Config:  s.config,
+		Genesis: *rec.Genesis,
+	}
+
+	// always verify the validator's blocks, since they must match the
+	// recorded payloads regardless of the client
+	networkBenchmark, err := network.NewNetworkBenchmark(config, s.log, nil, validatorOptions, nil, payload.Definition{}, s.portState, true, outputDir, live)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create network benchmark")
+	}
+	err = networkBenchmark.Replay(ctx, rec.ExecutionPayloads(), rec.FirstTestBlock)
+	clientVersions := networkBenchmark.ClientVersions()
+	if err != nil {
+		return nil, clientVersions, errors.Wrap(err, "failed to replay payloads")
+	}
+
+	err = s.exportOutput(testName, err, validatorOptions, outputDir, types.ValidatorRole)
+	if err != nil {
+		return nil, clientVersions, errors.Wrap(err, "failed to export validator output")
+	}
+
+	result, err := networkBenchmark.GetResult()
+	if err != nil {
+		return nil, clientVersions, errors.Wrap(err, "failed to get metrics")
+	}
+
+	return result, clientVersions, nil
+}
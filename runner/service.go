@@ -11,27 +11,35 @@ import (
 	"math/big"
 	"os"
 	"path"
-	"time"
+	"strings"
+	"sync"
 
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v3"
 
 	"github.com/base/base-bench/runner/benchmark"
 	"github.com/base/base-bench/runner/benchmark/portmanager"
+	clienttypes "github.com/base/base-bench/runner/clients/types"
 	"github.com/base/base-bench/runner/config"
 	"github.com/base/base-bench/runner/metrics"
 	"github.com/base/base-bench/runner/network"
+	"github.com/base/base-bench/runner/network/recording"
 	"github.com/base/base-bench/runner/network/types"
 	"github.com/base/base-bench/runner/payload"
+	"github.com/base/base-bench/runner/tracing"
 	"github.com/ethereum/go-ethereum/core"
 	ethparams "github.com/ethereum/go-ethereum/params"
 )
 
 var ErrAlreadyStopped = errors.New("already stopped")
 
+// HostInfoFileName is the name of the host fingerprint in each run's output
+// directory.
+const HostInfoFileName = "host.json"
+
 type Service interface {
 	Run(ctx context.Context) error
 }
@@ -46,9 +54,16 @@ type service struct {
 	config  config.Config
 	version string
 	log     log.Logger
+
+	// live exposes the progress of the benchmark runs on /metrics.
+	live *network.LiveMetrics
 }
 
 func NewService(version string, cfg config.Config, log log.Logger) Service {
+	return newService(version, cfg, log)
+}
+
+func newService(version string, cfg config.Config, log log.Logger) *service {
 	metadataPath := path.Join(cfg.OutputDir(), "metadata.json")
 
 	return &service{
@@ -58,20 +73,10 @@ func NewService(version string, cfg config.Config, log log.Logger) Service {
 		config:       cfg,
 		version:      version,
 		log:          log,
+		live:         network.NewLiveMetrics(),
 	}
 }
 
-func readBenchmarkConfig(path string) (*benchmark.BenchmarkConfig, error) {
-	file, err := os.OpenFile(path, os.O_RDONLY, 0)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to open file")
-	}
-
-	var config *benchmark.BenchmarkConfig
-	err = yaml.NewDecoder(file).Decode(&config)
-	return config, err
-}
-
 func (s *service) setupInternalDirectories(testDir string, params types.RunParams, genesis *core.Genesis, snapshot *benchmark.SnapshotDefinition, role string) (*config.InternalClientOptions, error) {
 	err := os.MkdirAll(testDir, 0755)
 	if err != nil {
@@ -101,7 +106,7 @@ func (s *service) setupInternalDirectories(testDir string, params types.RunParam
 	isSnapshot := snapshot != nil && snapshot.Command != ""
 	if isSnapshot {
 		// if we have a snapshot, restore it if needed or reuse from a previous test
-		snapshotDir, err := s.dataDirState.EnsureSnapshot(*snapshot, params.NodeType, role)
+		snapshotDir, err := s.dataDirState.EnsureSnapshot(*snapshot, params.NodeTypeFor(role), role)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to ensure snapshot")
 		}
@@ -168,6 +173,11 @@ func (s *service) exportOutput(testName string, returnedError error, testDirs *c
 	//  │   ├── result-<node_type>.json
 	//  │   ├── logs-<node_type>.gz
 	//  │   ├── metrics-<node_type>.json
+	//  │   ├── metrics-<node_type>.csv
+	//  │   ├── metrics-<node_type>.parquet
+	//  │   ├── metrics-series-<node_type>.json
+	//  │   ├── resources-<node_type>.json
+	//  │   ├── payloads.jsonl
 
 	// create output directory
 
@@ -179,11 +189,40 @@ func (s *service) exportOutput(testName string, returnedError error, testDirs *c
 		return errors.Wrap(err, "failed to move metrics file")
 	}
 
+	// copy metrics.csv and metrics.parquet to output dir if enabled
+	for _, filename := range []string{metrics.MetricsCSVFileName, metrics.MetricsParquetFileName} {
+		tablePath := path.Join(testDirs.MetricsPath, filename)
+		if _, err := os.Stat(tablePath); err == nil {
+			tableOutputPath := path.Join(testOutputDir, fmt.Sprintf("metrics-%s%s", nodeType, path.Ext(filename)))
+			if err := os.Rename(tablePath, tableOutputPath); err != nil {
+				return errors.Wrapf(err, "failed to move %s", filename)
+			}
+		}
+	}
+
+	// copy metrics-series.json to output dir
+	seriesPath := path.Join(testDirs.MetricsPath, metrics.MetricsSeriesFileName)
+	if _, err := os.Stat(seriesPath); err == nil {
+		seriesOutputPath := path.Join(testOutputDir, fmt.Sprintf("metrics-series-%s.json", nodeType))
+		if err := os.Rename(seriesPath, seriesOutputPath); err != nil {
+			return errors.Wrap(err, "failed to move metrics series file")
+		}
+	}
+
+	// copy resources.json to output dir if resource sampling is enabled
+	resourcesPath := path.Join(testDirs.MetricsPath, metrics.ResourcesFileName)
+	if _, err := os.Stat(resourcesPath); err == nil {
+		resourcesOutputPath := path.Join(testOutputDir, fmt.Sprintf("resources-%s.json", nodeType))
+		if err := os.Rename(resourcesPath, resourcesOutputPath); err != nil {
+			return errors.Wrap(err, "failed to move resources file")
+		}
+	}
+
 	// copy logs to output dir gzipped
 	logsPath := path.Join(testDirs.TestDirPath, network.ExecutionLayerLogFileName)
 	logsOutputPath := path.Join(testOutputDir, fmt.Sprintf("logs-%s.gz", nodeType))
 
-	outFile, err := os.OpenFile(logsOutputPath, os.O_WRONLY|os.O_CREATE, 0644)
+	outFile, err := os.OpenFile(logsOutputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return errors.Wrap(err, "failed to open logs file")
 	}
@@ -231,7 +270,7 @@ func (s *service) exportOutput(testName string, returnedError error, testDirs *c
 	}
 
 	resultPath := path.Join(testOutputDir, fmt.Sprintf("result-%s.json", nodeType))
-	resultFile, err := os.OpenFile(resultPath, os.O_WRONLY|os.O_CREATE, 0644)
+	resultFile, err := os.OpenFile(resultPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return errors.Wrap(err, "failed to open result file")
 	}
@@ -290,9 +329,7 @@ func (s *service) getGenesisForSnapshotConfig(snapshotConfig *benchmark.Snapshot
 	return genesis, nil
 }
 
-func (s *service) setupDataDirs(workingDir string, params types.RunParams, genesis *core.Genesis, snapshot *benchmark.SnapshotDefinition) (*config.InternalClientOptions, *config.InternalClientOptions, error) {
-	// create temp directory for this test
-	testName := fmt.Sprintf("%d-%s-test", time.Now().Unix(), params.NodeType)
+func (s *service) setupDataDirs(workingDir string, testName string, params types.RunParams, genesis *core.Genesis, snapshot *benchmark.SnapshotDefinition) (*config.InternalClientOptions, *config.InternalClientOptions, error) {
 	sequencerTestDir := path.Join(workingDir, fmt.Sprintf("%s-sequencer", testName))
 	validatorTestDir := path.Join(workingDir, fmt.Sprintf("%s-validator", testName))
 
@@ -319,30 +356,54 @@ func (s *service) setupBlobsDir(workingDir string) error {
 	return nil
 }
 
-func (s *service) runTest(ctx context.Context, params types.RunParams, workingDir string, outputDir string, snapshotConfig *benchmark.SnapshotDefinition, proofConfig *benchmark.ProofProgramOptions, transactionPayload payload.Definition) (*benchmark.RunResult, error) {
+func (s *service) runTest(ctx context.Context, run benchmark.TestRun, workingDir string, outputDir string, cpuSet string, snapshotConfig *benchmark.SnapshotDefinition, proofConfig *benchmark.ProofProgramOptions, thresholds *benchmark.ThresholdConfig, verifyConsistency bool, clientMetrics metrics.MetricsProfile, transactionPayload payload.Definition, live *network.LiveRun) (*benchmark.RunResult, map[string]*clienttypes.ClientVersion, error) {
+	params := run.Params
 
 	s.log.Info(fmt.Sprintf("Running benchmark with params: %+v", params))
 
 	// get genesis block
 	genesis, err := s.getGenesisForSnapshotConfig(snapshotConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get genesis block")
+		return nil, nil, errors.Wrap(err, "failed to get genesis block")
 	}
 
-	// create temp directory for this test
-	testName := fmt.Sprintf("%d-%s-test", time.Now().Unix(), params.NodeType)
+	// create temp directory for this test, named after the output dir so
+	// that parallel runs do not collide, and after both clients' node types,
+	// e.g. "rbuilder-reth" when the sequencer and validator differ
+	nodeTypes := strings.ReplaceAll(params.NodeTypeLabel(), "/", "-")
+	testName := fmt.Sprintf("%s-%s-test", path.Base(outputDir), nodeTypes)
 	sequencerTestDir := path.Join(workingDir, fmt.Sprintf("%s-sequencer", testName))
 	validatorTestDir := path.Join(workingDir, fmt.Sprintf("%s-validator", testName))
 
+	// a resumed run reuses the directories of its interrupted attempt, which
+	// were not cleaned up if the runner was killed
+	for _, testDir := range []string{sequencerTestDir, validatorTestDir} {
+		if _, err := os.Stat(testDir); err == nil {
+			s.log.Warn("Removing stale test directory", "dir", testDir)
+			if err := os.RemoveAll(testDir); err != nil {
+				return nil, nil, errors.Wrap(err, "failed to remove stale test directory")
+			}
+		}
+	}
+
 	// setup data directories (restore from snapshot if needed)
-	sequencerOptions, validatorOptions, err := s.setupDataDirs(workingDir, params, genesis, snapshotConfig)
+	sequencerOptions, validatorOptions, err := s.setupDataDirs(workingDir, testName, params, genesis, snapshotConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to setup data dirs")
+		return nil, nil, errors.Wrap(err, "failed to setup data dirs")
+	}
+	sequencerOptions.CPUSet = cpuSet
+	validatorOptions.CPUSet = cpuSet
+	sequencerOptions.DebugAPI = verifyConsistency
+	validatorOptions.DebugAPI = verifyConsistency
+
+	if clientMetrics != nil {
+		sequencerOptions.MetricsProfile = sequencerOptions.MetricsProfile.With(clientMetrics)
+		validatorOptions.MetricsProfile = validatorOptions.MetricsProfile.With(clientMetrics)
 	}
 
 	if proofConfig != nil {
 		if err := s.setupBlobsDir(workingDir); err != nil {
-			return nil, errors.Wrap(err, "failed to setup blobs directory")
+			return nil, nil, errors.Wrap(err, "failed to setup blobs directory")
 		}
 	}
 
@@ -363,18 +424,20 @@ func (s *service) runTest(ctx context.Context, params types.RunParams, workingDi
 	batcherKeyBytes := common.FromHex("0xd2ba8e70072983384203c438d4e94bf399cbd88bbcafb82b61cc96ed12541707")
 	batcherKey, err := crypto.ToECDSA(batcherKeyBytes)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create batcher key")
+		return nil, nil, errors.Wrap(err, "failed to create batcher key")
 	}
 
 	prefundKeyBytes := common.FromHex("0xad0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
 	prefundKey, err := crypto.ToECDSA(prefundKeyBytes)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create prefund key")
+		return nil, nil, errors.Wrap(err, "failed to create prefund key")
 	}
 
 	prefundAmount := new(big.Int).Mul(big.NewInt(1e6), big.NewInt(ethparams.Ether))
 	config := &types.TestConfig{
 		Params:            params,
+		RunID:             path.Base(outputDir),
+		Repetition:        run.Repetition,
 		Config:            s.config,
 		Genesis:           *genesis,
 		BatcherKey:        *batcherKey,
@@ -383,31 +446,66 @@ func (s *service) runTest(ctx context.Context, params types.RunParams, workingDi
 	}
 
 	// Run benchmark
-	benchmark, err := network.NewNetworkBenchmark(config, s.log, sequencerOptions, validatorOptions, proofConfig, transactionPayload, s.portState)
+	networkBenchmark, err := network.NewNetworkBenchmark(config, s.log, sequencerOptions, validatorOptions, proofConfig, transactionPayload, s.portState, verifyConsistency, outputDir, live)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create network benchmark")
+		return nil, nil, errors.Wrap(err, "failed to create network benchmark")
 	}
-	err = benchmark.Run(ctx)
+	err = networkBenchmark.Run(ctx)
+	clientVersions := networkBenchmark.ClientVersions()
+
+	// keep the built payloads even if the validator failed, so it can be
+	// debugged by replaying them
+	if rec := networkBenchmark.Recording(); rec != nil {
+		rec.Snapshot = snapshotConfig
+		if err := rec.Write(path.Join(outputDir, recording.FileName)); err != nil {
+			s.log.Error("Failed to write payload recording", "err", err)
+		}
+	}
+
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to run benchmark")
+		return nil, clientVersions, errors.Wrap(err, "failed to run benchmark")
 	}
 
 	err = s.exportOutput(testName, err, sequencerOptions, outputDir, "sequencer")
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to export sequencer output")
+		return nil, clientVersions, errors.Wrap(err, "failed to export sequencer output")
 	}
 
 	err = s.exportOutput(testName, err, validatorOptions, outputDir, "validator")
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to export validator output")
+		return nil, clientVersions, errors.Wrap(err, "failed to export validator output")
 	}
 
-	result, err := benchmark.GetResult()
+	result, err := networkBenchmark.GetResult()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get metrics")
+		return nil, clientVersions, errors.Wrap(err, "failed to get metrics")
+	}
+
+	sequencerBlockMetrics, validatorBlockMetrics := networkBenchmark.GetBlockMetrics()
+	result.ThresholdBreaches = thresholds.CheckThresholds(result, sequencerBlockMetrics, validatorBlockMetrics)
+	for _, breach := range result.ThresholdBreaches {
+		if breach.Level == benchmark.ThresholdLevelError {
+			s.log.Error("Threshold exceeded", "breach", breach.String())
+		} else {
+			s.log.Warn("Threshold exceeded", "breach", breach.String())
+		}
 	}
 
-	return result, nil
+	return result, clientVersions, nil
+}
+
+// writeHostInfo writes the host fingerprint to the run's output directory.
+func writeHostInfo(outputDir string, host *benchmark.HostInfo) error {
+	hostJSON, err := json.MarshalIndent(host, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal host info")
+	}
+
+	err = os.WriteFile(path.Join(outputDir, HostInfoFileName), hostJSON, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write host info")
+	}
+	return nil
 }
 
 func (s *service) readTestMetadata() ([]benchmark.Run, error) {
@@ -458,16 +556,87 @@ func (s *service) writeTestMetadata(testPlan benchmark.RunGroup) error {
 	return nil
 }
 
+func generateBenchmarkRunID() (string, error) {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", errors.Wrap(err, "failed to generate benchmark run id")
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// resumeTestMetadata restores the runs of a previous benchmark run into the
+// metadata so completed runs are skipped.
+func (s *service) resumeTestMetadata(metadata *benchmark.RunGroup, benchmarkRunID string) error {
+	existingRuns, err := s.readTestMetadata()
+	if err != nil {
+		return errors.Wrap(err, "failed to read existing metadata")
+	}
+
+	previousRuns := make([]benchmark.Run, 0)
+	for _, run := range existingRuns {
+		if run.TestConfig[benchmark.BenchmarkRunTag] == benchmarkRunID {
+			previousRuns = append(previousRuns, run)
+		}
+	}
+	if len(previousRuns) == 0 {
+		return fmt.Errorf("no runs found for benchmark run %s", benchmarkRunID)
+	}
+
+	return metadata.ResumeFrom(previousRuns)
+}
+
+// startTelemetry sets up trace export and starts serving live metrics if
+// enabled. The returned function flushes the traces and stops the server.
+func (s *service) startTelemetry(ctx context.Context) (func(), error) {
+	shutdownTracing, err := tracing.Setup(ctx, s.config.TracingOptions(), s.version)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set up tracing")
+	}
+	stopTracing := func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			s.log.Error("Failed to flush traces", "err", err)
+		}
+	}
+
+	metricsConfig := s.config.MetricsConfig()
+	if !metricsConfig.Enabled {
+		return stopTracing, nil
+	}
+
+	server, err := opmetrics.StartServer(s.live.Registry(), metricsConfig.ListenAddr, metricsConfig.ListenPort)
+	if err != nil {
+		stopTracing()
+		return nil, errors.Wrap(err, "failed to start metrics server")
+	}
+	s.log.Info("Serving live metrics", "addr", server.Addr())
+
+	stop := func() {
+		if err := server.Stop(context.Background()); err != nil {
+			s.log.Error("Failed to stop metrics server", "err", err)
+		}
+		stopTracing()
+	}
+	return stop, nil
+}
+
 func (s *service) Run(ctx context.Context) error {
 	s.log.Info("Starting")
 
-	config, err := readBenchmarkConfig(s.config.ConfigPath())
+	config, err := benchmark.ReadBenchmarkConfig(s.config.ConfigPath())
 	if err != nil {
-		return errors.Wrap(err, "failed to read benchmark config")
+		return errors.Wrap(err, "invalid benchmark config")
 	}
 
+	stopTelemetry, err := s.startTelemetry(ctx)
+	if err != nil {
+		return err
+	}
+	defer stopTelemetry()
+
 	numSuccess := 0
 	numFailure := 0
+	numThresholdErrors := 0
+	numDivergences := 0
 
 	var testPlans []benchmark.TestPlan
 
@@ -487,8 +656,28 @@ func (s *service) Run(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create output directory")
 	}
 
+	benchmarkRunID := s.config.ResumeBenchmarkRun()
+	resuming := benchmarkRunID != ""
+	if !resuming {
+		benchmarkRunID, err = generateBenchmarkRunID()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range testPlans {
+		for j := range testPlans[i].Runs {
+			testPlans[i].Runs[j].Params.BenchmarkRunID = benchmarkRunID
+		}
+	}
+
 	metadata := benchmark.RunGroupFromTestPlans(testPlans)
-	runIdx := 0
+	if resuming {
+		if err := s.resumeTestMetadata(&metadata, benchmarkRunID); err != nil {
+			return errors.Wrap(err, "failed to resume benchmark run")
+		}
+	}
+	s.log.Info("Benchmark run", "id", benchmarkRunID, "resuming", resuming)
 
 	// create map of transaction payloads
 	transactionPayloads := make(map[string]payload.Definition)
@@ -500,6 +689,102 @@ func (s *service) Run(ctx context.Context) error {
 		transactionPayloads[w.ID] = w
 	}
 
+	cpus, err := allowedCPUs()
+	if err != nil {
+		return err
+	}
+	cpuSets, err := cpuSetsForSlots(s.config.Parallelism(), cpus)
+	if err != nil {
+		return err
+	}
+
+	host := benchmark.CollectHostInfo(s.config.DataDir(), s.version)
+	s.log.Info("Host", "cpu", host.CPUModel, "cores", host.CPUCores, "memory", host.MemoryBytes, "kernel", host.Kernel, "filesystem", host.Filesystem)
+
+	// metadataLock guards metadata and the counters while runs execute in parallel
+	var metadataLock sync.Mutex
+
+	skipCompleted := func(idx int) bool {
+		metadataLock.Lock()
+		defer metadataLock.Unlock()
+
+		previous := metadata.Runs[idx].Result
+		if previous == nil || !previous.Success {
+			return false
+		}
+
+		s.log.Info("Skipping completed run", "outputDir", metadata.Runs[idx].OutputDir)
+		numSuccess++
+		if previous.HasErrorBreach() {
+			numThresholdErrors++
+		}
+		if previous.HasDivergence() {
+			numDivergences++
+		}
+		return true
+	}
+
+	executeRun := func(idx int, testPlan benchmark.TestPlan, c benchmark.TestRun, slot int, cpuSet string) error {
+		metadataLock.Lock()
+		outputDir := path.Join(s.config.OutputDir(), metadata.Runs[idx].OutputDir)
+		metadata.Runs[idx].Host = host
+		liveRun := s.live.StartRun(c.Params, metadata.Runs[idx].OutputDir)
+		if cpuSet != "" {
+			metadata.Runs[idx].Resources = &benchmark.ResourceAssignment{
+				Slot:   slot,
+				CPUSet: cpuSet,
+			}
+		}
+		metadataLock.Unlock()
+
+		if cpuSet != "" {
+			s.log.Info("Starting run", "outputDir", outputDir, "slot", slot, "cpuSet", cpuSet)
+		}
+
+		// ensure output directory exists
+		err := os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			return errors.Wrap(err, "failed to create output directory")
+		}
+
+		if err := writeHostInfo(outputDir, host); err != nil {
+			return err
+		}
+
+		metricSummary, clientVersions, err := s.runTest(ctx, c, s.config.DataDir(), outputDir, cpuSet, testPlan.Snapshot, testPlan.ProofProgram, testPlan.Thresholds, testPlan.VerifyConsistency, testPlan.ClientMetrics, transactionPayloads[c.Params.PayloadID], liveRun)
+		liveRun.Finish(err == nil)
+
+		metadataLock.Lock()
+		defer metadataLock.Unlock()
+
+		if err != nil {
+			log.Error("Failed to run test", "err", err)
+			metricSummary = &benchmark.RunResult{
+				Success:  false,
+				Complete: true,
+			}
+			numFailure++
+		} else {
+			numSuccess++
+			if metricSummary.HasErrorBreach() {
+				numThresholdErrors++
+			}
+			if metricSummary.HasDivergence() {
+				numDivergences++
+			}
+		}
+		metadata.Runs[idx].Clients = clientVersions
+		metadata.AddResult(idx, *metricSummary)
+
+		err = s.writeTestMetadata(metadata)
+		if err != nil {
+			return errors.Wrap(err, "failed to write test metadata")
+		}
+		return nil
+	}
+
+	runIdx := 0
+
 outerLoop:
 	for _, testPlan := range testPlans {
 		err = s.writeTestMetadata(metadata)
@@ -507,33 +792,28 @@ outerLoop:
 			return errors.Wrap(err, "failed to write test metadata")
 		}
 
-		for _, c := range testPlan.Runs {
-			outputDir := path.Join(s.config.OutputDir(), c.OutputDir)
-
-			// ensure output directory exists
-			err = os.MkdirAll(outputDir, 0755)
+		if len(cpuSets) > 1 && !requiresSerialExecution(testPlan, transactionPayloads) {
+			err := s.runParallel(ctx, testPlan, runIdx, cpuSets, skipCompleted, executeRun)
+			runIdx += len(testPlan.Runs)
 			if err != nil {
-				return errors.Wrap(err, "failed to create output directory")
+				return err
 			}
+			if ctx.Err() != nil {
+				break outerLoop
+			}
+			continue
+		}
 
-			metricSummary, err := s.runTest(ctx, c.Params, s.config.DataDir(), outputDir, testPlan.Snapshot, testPlan.ProofProgram, transactionPayloads[c.Params.PayloadID])
-			if err != nil {
-				log.Error("Failed to run test", "err", err)
-				metricSummary = &benchmark.RunResult{
-					Success:  false,
-					Complete: true,
-				}
-				numFailure++
-			} else {
-				numSuccess++
+		for _, c := range testPlan.Runs {
+			idx := runIdx
+			runIdx++
+			if skipCompleted(idx) {
+				continue
 			}
-			metadata.AddResult(runIdx, *metricSummary)
 
-			err = s.writeTestMetadata(metadata)
-			if err != nil {
-				return errors.Wrap(err, "failed to write test metadata")
+			if err := executeRun(idx, testPlan, c, 0, ""); err != nil {
+				return err
 			}
-			runIdx++
 
 			select {
 			case <-ctx.Done():
@@ -561,11 +841,19 @@ outerLoop:
 		return errors.Wrap(err, "failed to write test metadata")
 	}
 
-	s.log.Info("Finished benchmarking", "numSuccess", numSuccess, "numFailure", numFailure)
+	s.log.Info("Finished benchmarking", "numSuccess", numSuccess, "numFailure", numFailure, "numThresholdErrors", numThresholdErrors, "numDivergences", numDivergences)
 
 	if numFailure > 0 {
 		return fmt.Errorf("failed to run %d tests", numFailure)
 	}
 
+	if numThresholdErrors > 0 {
+		return fmt.Errorf("%d tests exceeded error thresholds", numThresholdErrors)
+	}
+
+	if numDivergences > 0 {
+		return fmt.Errorf("validator diverged from sequencer in %d tests", numDivergences)
+	}
+
 	return nil
 }
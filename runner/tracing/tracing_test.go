@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSetupFile(t *testing.T) {
+	file := path.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Options{File: file}, "test")
+	require.NoError(t, err)
+
+	ctx, block := Tracer().Start(context.Background(), "block")
+	_, call := Tracer().Start(ctx, "engine_newPayloadV4")
+	call.SetAttributes(attribute.Int64("gas_used", 21000))
+	End(call, errors.New("newPayload call failed"))
+	End(block, nil)
+
+	require.NoError(t, shutdown(context.Background()))
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+
+	type span struct {
+		Name   string
+		Parent struct {
+			SpanID string
+		}
+		SpanContext struct {
+			SpanID string
+		}
+		Status struct {
+			Code        string
+			Description string
+		}
+	}
+	var spans []span
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		var s span
+		require.NoError(t, decoder.Decode(&s))
+		spans = append(spans, s)
+	}
+
+	require.Len(t, spans, 2)
+	require.Equal(t, "engine_newPayloadV4", spans[0].Name)
+	require.Equal(t, "Error", spans[0].Status.Code)
+	require.Equal(t, "newPayload call failed", spans[0].Status.Description)
+	require.Equal(t, "block", spans[1].Name)
+	require.Equal(t, spans[1].SpanContext.SpanID, spans[0].Parent.SpanID)
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{}, "test")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}